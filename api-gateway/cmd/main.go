@@ -9,9 +9,11 @@ import (
 	"syscall"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"api-gateway/internal/auth"
 	"api-gateway/internal/config"
 	"api-gateway/internal/handler"
 	"api-gateway/internal/middleware"
@@ -45,6 +47,7 @@ func main() {
 	// Create gRPC clients
 	inventoryClient := pbinv.NewInventoryServiceClient(inventoryConn)
 	orderClient := pborder.NewOrderServiceClient(orderConn)
+	cartClient := pborder.NewCartServiceClient(orderConn)
 	userClient := pbuser.NewUserServiceClient(userConn)
 
 	// Setup Gin
@@ -53,8 +56,9 @@ func main() {
 	// Add CORS middleware
 	router.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000") // Your frontend URL
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+handler.CartHeader+", "+handler.IdempotencyKeyHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Link, "+handler.CartHeader)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		// Handle OPTIONS requests (CORS preflight)
@@ -67,24 +71,54 @@ func main() {
 	})
 
 	router.Use(middleware.LoggingMiddleware())
-	// router.Use(middleware.AuthMiddleware()) // Uncomment if you want auth
 
-	h := handler.NewGatewayHandler(inventoryClient, orderClient, userClient)
+	verifier, err := auth.NewTokenVerifier(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize token verifier: %v", err)
+	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	defer redisClient.Close()
+	denylist := auth.NewDenylist(redisClient)
+
+	protected := router.Group("/", middleware.AuthMiddleware(verifier, denylist), middleware.Authorize())
+
+	h := handler.NewGatewayHandler(inventoryClient, orderClient, cartClient, userClient, verifier)
 
 	// Product routes
-	router.POST("/products", h.CreateProduct)
+	router.GET("/products/search", h.SearchProducts)
+	router.GET("/products/suggest", h.SuggestProducts)
 	router.GET("/products/:id", h.GetProduct)
 	router.GET("/products", h.ListProducts)
-	router.PUT("/products/:id", h.UpdateProduct)
+	protected.POST("/products", h.CreateProduct)
+	protected.PUT("/products/:id", h.UpdateProduct)
+	protected.PATCH("/products/:id", h.PatchProduct)
 
 	// Order routes
-	router.POST("/orders", h.CreateOrder)
-	router.GET("/orders/:id", h.GetOrder)
-	router.GET("/orders", h.ListOrders)
+	protected.POST("/orders", h.CreateOrder)
+	protected.GET("/orders/:id", h.GetOrder)
+	protected.GET("/orders/:id/history", h.GetOrderHistory)
+	protected.POST("/orders/:id/cancel", h.CancelOrder)
+	protected.GET("/orders", h.ListOrders)
+
+	// Cart routes, open to guests who identify their cart with X-Cart-ID
+	cart := router.Group("/cart", middleware.OptionalAuthMiddleware(verifier, denylist), middleware.Authorize())
+	cart.GET("", h.GetCart)
+	cart.POST("/items", h.AddCartItem)
+	cart.PUT("/items/:product_id", h.UpdateCartItem)
+	cart.DELETE("/items/:product_id", h.RemoveCartItem)
+	cart.POST("/merge", h.MergeCart)
+	cart.POST("/checkout", h.CheckoutCart)
 
 	// User routes
 	router.POST("/users/register", h.RegisterUser)
 	router.POST("/users/login", h.AuthenticateUser)
+	router.POST("/users/refresh", h.RefreshToken)
+	protected.POST("/users/logout", h.Logout)
+	protected.POST("/users/logout-all", h.LogoutAllSessions)
 
 	// Health and debug routes
 	router.GET("/health", func(c *gin.Context) {
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Keys written by user-service when tokens are revoked.
+const (
+	denylistTokenPrefix = "denylist:jti:"
+	denylistUserPrefix  = "denylist:user:"
+)
+
+type Denylist struct {
+	client *redis.Client
+}
+
+func NewDenylist(client *redis.Client) *Denylist {
+	return &Denylist{client: client}
+}
+
+// IsRevoked reports whether the token was logged out on its own, or was
+// issued before its user logged out of all sessions.
+func (d *Denylist) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	pipe := d.client.Pipeline()
+	tokenCmd := pipe.Exists(ctx, denylistTokenPrefix+claims.ID)
+	userCmd := pipe.Get(ctx, denylistUserPrefix+claims.Subject)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if tokenCmd.Val() > 0 {
+		return true, nil
+	}
+	// Both times are in milliseconds. Compare strictly, so a login right
+	// after the logout is not rejected
+	if before, err := strconv.ParseInt(userCmd.Val(), 10, 64); err == nil && claims.IssuedAt != nil {
+		return claims.IssuedAt.Round(time.Millisecond).UnixMilli() < before, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"api-gateway/internal/config"
+	"authz"
+)
+
+// user-service issues tokens with millisecond timestamps, so logging out of
+// all sessions can cut off the tokens issued earlier in the same second.
+// They are decoded as floats, which can land just under the millisecond, so
+// parse them finer than that rather than truncating; Denylist rounds.
+func init() {
+	jwt.TimePrecision = time.Microsecond
+}
+
+// Claims mirrors the access token payload issued by user-service.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type TokenVerifier struct {
+	method string
+	key    interface{}
+	issuer string
+}
+
+// NewTokenVerifier checks HS256 tokens against the shared secret, or RS256
+// tokens against the PEM public key at JWTPublicKeyPath.
+func NewTokenVerifier(cfg *config.Config) (*TokenVerifier, error) {
+	v := &TokenVerifier{
+		method: cfg.JWTAlgorithm,
+		issuer: cfg.JWTIssuer,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if len(cfg.JWTSecret) < authz.MinSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET must be set to at least %d bytes for HS256", authz.MinSecretLength)
+		}
+		v.key = []byte(cfg.JWTSecret)
+	case "RS256":
+		pem, err := os.ReadFile(cfg.JWTPublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %v", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %v", err)
+		}
+		v.key = key
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.JWTAlgorithm)
+	}
+
+	return v, nil
+}
+
+// Verify checks the token's signature, algorithm, issuer and expiry and
+// returns its claims.
+func (v *TokenVerifier) Verify(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(*jwt.Token) (interface{}, error) { return v.key, nil },
+		jwt.WithValidMethods([]string{v.method}),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("token has no subject")
+	}
+	return claims, nil
+}
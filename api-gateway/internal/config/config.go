@@ -1,10 +1,21 @@
 package config
 
+import "os"
+
 type Config struct {
 	HTTPPort             string
 	InventoryServiceAddr string
 	OrderServiceAddr     string
 	UserServiceAddr      string
+
+	JWTAlgorithm     string
+	JWTSecret        string
+	JWTPublicKeyPath string
+	JWTIssuer        string
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
 }
 
 func NewConfig() *Config {
@@ -13,5 +24,21 @@ func NewConfig() *Config {
 		InventoryServiceAddr: "localhost:8080",
 		OrderServiceAddr:     "localhost:8081",
 		UserServiceAddr:      "localhost:50051",
+
+		JWTAlgorithm:     getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyPath: getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", "user-service"),
+
+		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       0,
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
 	}
+	return defaultValue
 }
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"api-gateway/internal/middleware"
+	pborder "api-gateway/proto/order"
+	"authz"
+)
+
+// CartHeader carries a guest's cart ID. The gateway hands out a new one on
+// the first cart request a guest makes; sending it along with the login
+// request merges the guest cart into the user's.
+const CartHeader = "X-Cart-ID"
+
+func (h *GatewayHandler) GetCart(c *gin.Context) {
+	req := &pborder.GetCartRequest{CartId: guestCartID(c)}
+	res, err := h.cartClient.GetCart(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) AddCartItem(c *gin.Context) {
+	var req pborder.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.CartId = guestCartID(c)
+
+	res, err := h.cartClient.AddCartItem(c.Request.Context(), &req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) UpdateCartItem(c *gin.Context) {
+	var req pborder.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.CartId = guestCartID(c)
+	req.ProductId = c.Param("product_id")
+
+	res, err := h.cartClient.UpdateCartItem(c.Request.Context(), &req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) RemoveCartItem(c *gin.Context) {
+	req := &pborder.RemoveCartItemRequest{
+		CartId:    guestCartID(c),
+		ProductId: c.Param("product_id"),
+	}
+	res, err := h.cartClient.RemoveCartItem(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// MergeCart moves the guest cart named by CartHeader into the caller's cart.
+func (h *GatewayHandler) MergeCart(c *gin.Context) {
+	guestID := c.GetHeader(CartHeader)
+	if guestID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": CartHeader + " header required"})
+		return
+	}
+
+	res, err := h.cartClient.MergeCart(c.Request.Context(), &pborder.MergeCartRequest{GuestCartId: guestID})
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// CheckoutCart places an order for the caller's cart. When prices changed
+// since the cart was last read the cart is repriced and 409 is returned with
+// the new quote.
+func (h *GatewayHandler) CheckoutCart(c *gin.Context) {
+	res, err := h.cartClient.Checkout(c.Request.Context(), &pborder.CheckoutRequest{})
+	if err != nil {
+		handleOrderError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
+
+// mergeGuestCart merges a guest cart into the cart of the user who just
+// logged in with token. Logging in still succeeds if the merge fails.
+func (h *GatewayHandler) mergeGuestCart(c *gin.Context, token, guestID string) {
+	claims, err := h.verifier.Verify(token)
+	if err != nil {
+		log.Printf("Failed to verify new token for cart merge: %v", err)
+		return
+	}
+
+	ctx := authz.AppendToOutgoingContext(c.Request.Context(), token)
+	if _, err := h.cartClient.MergeCart(ctx, &pborder.MergeCartRequest{GuestCartId: guestID}); err != nil {
+		log.Printf("Failed to merge guest cart into cart of user %s: %v", claims.Subject, err)
+	}
+}
+
+// guestCartID returns the cart ID to send for a guest, handing out a new one
+// when the request has none, and echoes it in CartHeader. Logged-in callers
+// need none, since their cart is found by who they are.
+func guestCartID(c *gin.Context) string {
+	if _, loggedIn := c.Get(middleware.ClaimsKey); loggedIn {
+		return ""
+	}
+
+	id := c.GetHeader(CartHeader)
+	if id == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			log.Printf("Failed to generate cart id: %v", err)
+			return ""
+		}
+		id = hex.EncodeToString(b)
+	}
+	c.Header(CartHeader, id)
+	return id
+}
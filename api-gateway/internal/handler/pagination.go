@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageParams are the paging query parameters shared by the list routes.
+type pageParams struct {
+	limit     int32
+	pageToken string
+	withTotal bool
+}
+
+func parsePageParams(c *gin.Context) (pageParams, error) {
+	params := pageParams{pageToken: c.Query("page_token")}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.ParseInt(limit, 10, 32)
+		if err != nil || n < 0 {
+			return params, fmt.Errorf("invalid limit %q", limit)
+		}
+		params.limit = int32(n)
+	}
+	withTotal, err := queryBool(c, "include_total_count")
+	if err != nil {
+		return params, err
+	}
+	params.withTotal = withTotal
+	return params, nil
+}
+
+// queryFloat reads an optional numeric query parameter.
+func queryFloat(c *gin.Context, key string) (float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return f, nil
+}
+
+// queryInt64 reads an optional integer query parameter.
+func queryInt64(c *gin.Context, key string) (int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return n, nil
+}
+
+// queryBool reads an optional boolean query parameter.
+func queryBool(c *gin.Context, key string) (bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return b, nil
+}
+
+// writePage responds with a page of a listing in the envelope every list
+// route uses:
+//
+//	{"data": [...], "next_page_token": "...", "total_count": 42}
+//
+// total_count is only present when it was asked for. The Link header points
+// at the first and, unless this is the last page, the next page.
+func writePage(c *gin.Context, data interface{}, nextPageToken string, totalCount *int64) {
+	c.JSON(http.StatusOK, pageBody(c, data, nextPageToken, totalCount))
+}
+
+// pageBody sets the Link header of a page and returns its envelope, for
+// routes that add fields of their own to it.
+func pageBody(c *gin.Context, data interface{}, nextPageToken string, totalCount *int64) gin.H {
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(c, ""))}
+	if nextPageToken != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(c, nextPageToken)))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+
+	body := gin.H{"data": data, "next_page_token": nextPageToken}
+	if totalCount != nil {
+		body["total_count"] = *totalCount
+	}
+	return body
+}
+
+// pageURL is the current request URL with page_token set to token, or
+// removed when token is empty.
+func pageURL(c *gin.Context, token string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	if token == "" {
+		query.Del("page_token")
+	} else {
+		query.Set("page_token", token)
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
@@ -1,32 +1,45 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"sort"
 
+	"api-gateway/internal/auth"
 	pbinv "api-gateway/proto/inventory"
 	pborder "api-gateway/proto/order"
 	pbuser "api-gateway/proto/user"
 
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type GatewayHandler struct {
 	inventoryClient pbinv.InventoryServiceClient
 	orderClient     pborder.OrderServiceClient
+	cartClient      pborder.CartServiceClient
 	userClient      pbuser.UserServiceClient
+	verifier        *auth.TokenVerifier
 }
 
 func NewGatewayHandler(
 	inventoryClient pbinv.InventoryServiceClient,
 	orderClient pborder.OrderServiceClient,
+	cartClient pborder.CartServiceClient,
 	userClient pbuser.UserServiceClient,
+	verifier *auth.TokenVerifier,
 ) *GatewayHandler {
 	return &GatewayHandler{
 		inventoryClient: inventoryClient,
 		orderClient:     orderClient,
+		cartClient:      cartClient,
 		userClient:      userClient,
+		verifier:        verifier,
 	}
 }
 
@@ -55,33 +68,175 @@ func (h *GatewayHandler) GetProduct(c *gin.Context) {
 }
 
 func (h *GatewayHandler) ListProducts(c *gin.Context) {
-	var req pbinv.ListProductsRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		// Ignore error, just use default zero values if not provided
+	page, err := parsePageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &pbinv.ListProductsRequest{
+		Name:              c.Query("name"),
+		Category:          c.Query("category"),
+		OrderBy:           c.Query("order_by"),
+		Limit:             page.limit,
+		PageToken:         page.pageToken,
+		IncludeTotalCount: page.withTotal,
+	}
+	if req.MinPrice, err = queryFloat(c, "min_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxPrice, err = queryFloat(c, "max_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.InStockOnly, err = queryBool(c, "in_stock_only"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.inventoryClient.ListProducts(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	products := res.Products
+	if products == nil {
+		products = []*pbinv.ProductResponse{}
+	}
+	writePage(c, products, res.NextPageToken, res.TotalCount)
+}
+
+// SearchProducts runs a full-text search. Hits come most relevant first and
+// the envelope carries facet counts over all matching products:
+//
+//	{"data": [...], "next_page_token": "...", "total_count": 42,
+//	 "facets": {"categories": [...], "price_buckets": [...]}}
+func (h *GatewayHandler) SearchProducts(c *gin.Context) {
+	page, err := parsePageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &pbinv.SearchProductsRequest{
+		Query:     c.Query("q"),
+		Category:  c.Query("category"),
+		Limit:     page.limit,
+		PageToken: page.pageToken,
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	if req.MinPrice, err = queryFloat(c, "min_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxPrice, err = queryFloat(c, "max_price"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.InStockOnly, err = queryBool(c, "in_stock_only"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.inventoryClient.SearchProducts(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	hits := res.Hits
+	if hits == nil {
+		hits = []*pbinv.SearchHit{}
+	}
+	categories := res.Categories
+	if categories == nil {
+		categories = []*pbinv.FacetCount{}
+	}
+	priceBuckets := res.PriceBuckets
+	if priceBuckets == nil {
+		priceBuckets = []*pbinv.PriceBucket{}
+	}
+
+	body := pageBody(c, hits, res.NextPageToken, &res.TotalCount)
+	body["facets"] = gin.H{"categories": categories, "price_buckets": priceBuckets}
+	c.JSON(http.StatusOK, body)
+}
+
+// SuggestProducts completes what a shopper has typed so far with product
+// names and categories, most popular first.
+func (h *GatewayHandler) SuggestProducts(c *gin.Context) {
+	page, err := parsePageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &pbinv.SuggestProductsRequest{
+		Prefix: c.Query("prefix"),
+		Limit:  page.limit,
+	}
+	if req.Prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "prefix is required"})
+		return
 	}
-	res, err := h.inventoryClient.ListProducts(c.Request.Context(), &req)
+
+	res, err := h.inventoryClient.SuggestProducts(c.Request.Context(), req)
 	if err != nil {
 		handleGRPCError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, res.Products) // ✅ CORRECT: return only the array
+	products := res.Products
+	if products == nil {
+		products = []*pbinv.ProductSuggestion{}
+	}
+	categories := res.Categories
+	if categories == nil {
+		categories = []*pbinv.CategorySuggestion{}
+	}
+	c.JSON(http.StatusOK, gin.H{"products": products, "categories": categories})
 }
 
 
+// IdempotencyKeyHeader lets clients retry POST /orders safely: a repeat of a
+// request with the same key returns the order the first one created.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 func (h *GatewayHandler) CreateOrder(c *gin.Context) {
 	var req pborder.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	res, err := h.orderClient.CreateOrder(c.Request.Context(), &req)
+	ctx := c.Request.Context()
+	if key := c.GetHeader(IdempotencyKeyHeader); key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "idempotency-key", key)
+	}
+	res, err := h.orderClient.CreateOrder(ctx, &req)
 	if err != nil {
-		handleGRPCError(c, err)
+		handleOrderError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, res)
 }
 
+// handleOrderError is handleGRPCError for calls that place an order: a stale
+// total comes back with the current pricing attached, and an Idempotency-Key
+// reused for a different request is rejected with 422.
+func handleOrderError(c *gin.Context, err error) {
+	st := status.Convert(err)
+	if st.Code() == codes.AlreadyExists {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": st.Message()})
+		return
+	}
+	for _, detail := range st.Details() {
+		if quote, ok := detail.(*pborder.Quote); ok {
+			c.JSON(http.StatusConflict, gin.H{"error": st.Message(), "quote": quote})
+			return
+		}
+	}
+	handleGRPCError(c, err)
+}
+
 func (h *GatewayHandler) GetOrder(c *gin.Context) {
 	req := &pborder.GetOrderRequest{Id: c.Param("id")}
 	res, err := h.orderClient.GetOrder(c.Request.Context(), req)
@@ -92,12 +247,34 @@ func (h *GatewayHandler) GetOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
-func (h *GatewayHandler) ListOrders(c *gin.Context) {
-	var req pborder.ListOrdersRequest
-	if err := c.ShouldBindQuery(&req); err != nil {
-		// Ignore error, just use default zero values if not provided
+// GetOrderHistory returns the status timeline of an order.
+func (h *GatewayHandler) GetOrderHistory(c *gin.Context) {
+	req := &pborder.GetOrderRequest{Id: c.Param("id")}
+	res, err := h.orderClient.GetOrder(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
 	}
-	res, err := h.orderClient.ListOrders(c.Request.Context(), &req)
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": res.Id,
+		"status":   res.Status,
+		"history":  res.StatusHistory,
+	})
+}
+
+// CancelOrder cancels an order that has not been paid yet. The JSON body is
+// optional and may carry a reason.
+func (h *GatewayHandler) CancelOrder(c *gin.Context) {
+	var req pborder.CancelOrderRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	req.Id = c.Param("id")
+
+	res, err := h.orderClient.CancelOrder(c.Request.Context(), &req)
 	if err != nil {
 		handleGRPCError(c, err)
 		return
@@ -105,6 +282,50 @@ func (h *GatewayHandler) ListOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, res)
 }
 
+func (h *GatewayHandler) ListOrders(c *gin.Context) {
+	page, err := parsePageParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req := &pborder.ListOrdersRequest{
+		UserId:            c.Query("user_id"),
+		Status:            c.Query("status"),
+		ProductId:         c.Query("product_id"),
+		OrderBy:           c.Query("order_by"),
+		Limit:             page.limit,
+		PageToken:         page.pageToken,
+		IncludeTotalCount: page.withTotal,
+	}
+	if req.CreatedAfter, err = queryInt64(c, "created_after"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CreatedBefore, err = queryInt64(c, "created_before"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MinTotal, err = queryFloat(c, "min_total"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.MaxTotal, err = queryFloat(c, "max_total"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.orderClient.ListOrders(c.Request.Context(), req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	orders := res.Orders
+	if orders == nil {
+		orders = []*pborder.OrderResponse{}
+	}
+	writePage(c, orders, res.NextPageToken, res.TotalCount)
+}
+
 func (h *GatewayHandler) RegisterUser(c *gin.Context) {
 	var req pbuser.RegisterUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -130,6 +351,47 @@ func (h *GatewayHandler) AuthenticateUser(c *gin.Context) {
 		handleGRPCError(c, err)
 		return
 	}
+	if guestID := c.GetHeader(CartHeader); guestID != "" {
+		h.mergeGuestCart(c, res.Token, guestID)
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) RefreshToken(c *gin.Context) {
+	var req pbuser.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.userClient.RefreshToken(c.Request.Context(), &req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) Logout(c *gin.Context) {
+	var req pbuser.LogoutRequest
+	// The refresh token is optional, so an empty body is fine
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	res, err := h.userClient.Logout(c.Request.Context(), &req)
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *GatewayHandler) LogoutAllSessions(c *gin.Context) {
+	res, err := h.userClient.LogoutAllSessions(c.Request.Context(), &pbuser.LogoutAllSessionsRequest{})
+	if err != nil {
+		handleGRPCError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, res)
 }
 
@@ -148,6 +410,15 @@ func handleGRPCError(c *gin.Context, err error) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": st.Message()})
 	case codes.AlreadyExists:
 		c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+	case codes.PermissionDenied:
+		c.JSON(http.StatusForbidden, gin.H{"error": st.Message()})
+	case codes.FailedPrecondition:
+		c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+	case codes.Aborted:
+		// A concurrent request got in the way; retrying may succeed
+		c.JSON(http.StatusConflict, gin.H{"error": st.Message()})
+	case codes.Unavailable:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": st.Message()})
 	default:
 		c.JSON(http.StatusInternalServerError, gin.H{"error": st.Message()})
 	}
@@ -162,6 +433,44 @@ func (h *GatewayHandler) UpdateProduct(c *gin.Context) {
     }
     req.Id = id // Ensure ID from URL is used
     
+    res, err := h.inventoryClient.UpdateProduct(c.Request.Context(), &req)
+    if err != nil {
+        handleGRPCError(c, err)
+        return
+    }
+    c.JSON(http.StatusOK, res)
+}
+
+// PatchProduct updates only the fields present in the JSON body by turning
+// its keys into the update mask.
+func (h *GatewayHandler) PatchProduct(c *gin.Context) {
+    body, err := io.ReadAll(c.Request.Body)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    var fields map[string]json.RawMessage
+    if err := json.Unmarshal(body, &fields); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+
+    var req pbinv.ProductRequest
+    if err := json.Unmarshal(body, &req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+        return
+    }
+    req.Id = c.Param("id")
+    req.UpdateMask = &fieldmaskpb.FieldMask{}
+    for field := range fields {
+        if field == "id" {
+            continue
+        }
+        req.UpdateMask.Paths = append(req.UpdateMask.Paths, field)
+    }
+    sort.Strings(req.UpdateMask.Paths)
+
     res, err := h.inventoryClient.UpdateProduct(c.Request.Context(), &req)
     if err != nil {
         handleGRPCError(c, err)
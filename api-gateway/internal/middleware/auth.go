@@ -6,9 +6,15 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/grpc/metadata"
+
+	"api-gateway/internal/auth"
+	"authz"
 )
 
+// ClaimsKey is the gin context key under which AuthMiddleware stores the
+// verified token claims.
+const ClaimsKey = "claims"
+
 func LoggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		log.Printf("Request: %s %s", c.Request.Method, c.Request.URL.Path)
@@ -16,23 +22,86 @@ func LoggingMiddleware() gin.HandlerFunc {
 	}
 }
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware verifies the Bearer token and forwards it to downstream
+// services, which verify it again and take the caller's identity from it.
+func AuthMiddleware(verifier *auth.TokenVerifier, denylist *auth.Denylist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authenticate(c, verifier, denylist) {
+			c.Next()
+		}
+	}
+}
+
+// OptionalAuthMiddleware is AuthMiddleware for routes guests may use too:
+// requests without an Authorization header pass through anonymously, while
+// a token that is sent must still be valid.
+func OptionalAuthMiddleware(verifier *auth.TokenVerifier, denylist *auth.Denylist) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		if authenticate(c, verifier, denylist) {
+			c.Next()
+		}
+	}
+}
+
+// authenticate verifies the request's token and attaches the caller's
+// identity. It aborts the request and returns false when that fails.
+func authenticate(c *gin.Context, verifier *auth.TokenVerifier, denylist *auth.Denylist) bool {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+		return false
+	}
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" || token == authHeader {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+		return false
+	}
+
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return false
+	}
+
+	revoked, err := denylist.IsRevoked(c.Request.Context(), claims)
+	if err != nil {
+		log.Printf("Token denylist check failed: %v", err)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "unable to verify token"})
+		return false
+	}
+	if revoked {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+		return false
+	}
+	c.Set(ClaimsKey, claims)
+
+	c.Request = c.Request.WithContext(authz.AppendToOutgoingContext(c.Request.Context(), token))
+	return true
+}
+
+// Authorize enforces authz.Routes for the matched route. It must run after
+// AuthMiddleware so the caller's claims are available.
+func Authorize() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+		route := c.Request.Method + " " + c.FullPath()
+		roles, listed := authz.Routes[route]
+		if !listed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "route is not allowed"})
 			return
 		}
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "bearer token required"})
+
+		var role authz.Role
+		if claims, ok := c.Get(ClaimsKey); ok {
+			role = authz.Role(claims.(*auth.Claims).Role)
+		}
+		if len(roles) > 0 && !authz.Allowed(roles, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
 			return
 		}
-		ctx := metadata.AppendToOutgoingContext(
-			c.Request.Context(),
-			"authorization", authHeader,
-		)
-		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
 }
@@ -0,0 +1,57 @@
+package authz
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationKey is the metadata key carrying the caller's bearer token:
+// the user's access token forwarded by the gateway, or a service token.
+const AuthorizationKey = "authorization"
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+func (id Identity) IsAdmin() bool {
+	return id.Role == RoleAdmin
+}
+
+type identityKey struct{}
+
+// AppendToOutgoingContext forwards the caller's bearer token to the next
+// gRPC call.
+func AppendToOutgoingContext(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, AuthorizationKey, "Bearer "+strings.TrimPrefix(token, "Bearer "))
+}
+
+// FromIncomingMetadata verifies the bearer token the caller sent, if any,
+// and returns the identity it was issued to. ok is false when no token was
+// sent; err is set when one was sent but is not valid.
+func FromIncomingMetadata(ctx context.Context, verifier *Verifier) (id Identity, ok bool, err error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(AuthorizationKey)
+	if len(values) == 0 || values[0] == "" {
+		return Identity{}, false, nil
+	}
+	id, err = verifier.Verify(values[0])
+	if err != nil {
+		return Identity{}, false, err
+	}
+	return id, true, nil
+}
+
+// NewContext stores the identity for handlers further down the chain.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity stored by UnaryServerInterceptor.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
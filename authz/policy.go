@@ -0,0 +1,103 @@
+package authz
+
+// Public marks a route or method that needs no authenticated caller.
+var Public = []Role{}
+
+var (
+	anyUser      = []Role{RoleCustomer, RoleAdmin}
+	adminOnly    = []Role{RoleAdmin}
+	adminService = []Role{RoleAdmin, RoleService}
+	anyCaller    = []Role{RoleCustomer, RoleAdmin, RoleService}
+)
+
+// Routes maps gateway routes, as "METHOD /gin/full/path", to the roles
+// allowed to call them. Routes not listed here are denied.
+var Routes = map[string][]Role{
+	"GET /health":       Public,
+	"GET /debug/routes": Public,
+
+	"GET /products":         Public,
+	"GET /products/search":  Public,
+	"GET /products/suggest": Public,
+	"GET /products/:id":     Public,
+	"POST /products":        adminOnly,
+	"PUT /products/:id":     adminOnly,
+	"PATCH /products/:id":   adminOnly,
+
+	"POST /orders":            anyUser,
+	"GET /orders":             anyUser,
+	"GET /orders/:id":         anyUser,
+	"GET /orders/:id/history": anyUser,
+	"POST /orders/:id/cancel": anyUser,
+
+	// Guests shop with a cart ID instead of a token
+	"GET /cart":                      Public,
+	"POST /cart/items":               Public,
+	"PUT /cart/items/:product_id":    Public,
+	"DELETE /cart/items/:product_id": Public,
+	"POST /cart/merge":               anyUser,
+	"POST /cart/checkout":            anyUser,
+
+	"POST /users/register":   Public,
+	"POST /users/login":      Public,
+	"POST /users/refresh":    Public,
+	"POST /users/logout":     anyUser,
+	"POST /users/logout-all": anyUser,
+}
+
+// Methods maps gRPC full method names to the roles allowed to call them.
+// Methods not listed here are denied.
+var Methods = map[string][]Role{
+	"/grpc.health.v1.Health/Check": Public,
+
+	"/inventory.InventoryService/GetProduct":        Public,
+	"/inventory.InventoryService/ListProducts":      Public,
+	"/inventory.InventoryService/SearchProducts":    Public,
+	"/inventory.InventoryService/SuggestProducts":   Public,
+	"/inventory.InventoryService/CreateProduct":     adminOnly,
+	"/inventory.InventoryService/UpdateProduct":     adminOnly,
+	"/inventory.InventoryService/DeleteProduct":     adminOnly,
+	"/inventory.InventoryService/ReserveStock":      adminService,
+	"/inventory.InventoryService/ReleaseStock":      adminService,
+	"/inventory.InventoryService/CommitReservation": adminService,
+	"/inventory.InventoryService/ReturnStock":       adminService,
+
+	"/order.OrderService/CreateOrder":       anyUser,
+	"/order.OrderService/GetOrder":          anyCaller,
+	"/order.OrderService/ListOrders":        anyCaller,
+	"/order.OrderService/UpdateOrderStatus": adminService,
+	"/order.OrderService/CancelOrder":       anyCaller,
+
+	"/order.CartService/GetCart":        Public,
+	"/order.CartService/AddCartItem":    Public,
+	"/order.CartService/UpdateCartItem": Public,
+	"/order.CartService/RemoveCartItem": Public,
+	"/order.CartService/MergeCart":      anyUser,
+	"/order.CartService/Checkout":       anyUser,
+
+	"/user.UserService/RegisterUser":      Public,
+	"/user.UserService/AuthenticateUser":  Public,
+	"/user.UserService/RefreshToken":      Public,
+	"/user.UserService/HealthCheck":       Public,
+	"/user.UserService/GetUserProfile":    anyCaller,
+	"/user.UserService/Logout":            anyUser,
+	"/user.UserService/LogoutAllSessions": anyUser,
+}
+
+// Allowed reports whether a caller with the given role may use an endpoint
+// that requires one of roles. A nil roles slice means the endpoint is not in
+// the policy and is always denied.
+func Allowed(roles []Role, role Role) bool {
+	if roles == nil {
+		return false
+	}
+	if len(roles) == 0 {
+		return true
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
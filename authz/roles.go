@@ -0,0 +1,13 @@
+// Package authz holds the role-based access policy shared by the API gateway
+// and the gRPC services, together with the helpers that enforce it.
+package authz
+
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleAdmin    Role = "admin"
+	// RoleService identifies internal callers such as consumer-service and
+	// order-service. Only service tokens carry it.
+	RoleService Role = "service"
+)
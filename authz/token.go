@@ -0,0 +1,126 @@
+package authz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ServiceIssuer is the issuer of the service tokens minted by
+// NewServiceCredentials.
+const ServiceIssuer = "service"
+
+// MinSecretLength is the shortest HS256 secret accepted: 256 bits, the size
+// of the hash.
+const MinSecretLength = 32
+
+// Claims is the payload of the tokens the services accept: access tokens
+// issued by user-service, and service tokens.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// VerifierConfig describes how the tokens a service accepts are signed.
+type VerifierConfig struct {
+	// Algorithm, Secret, PublicKeyPath and Issuer describe the access tokens
+	// issued by user-service: HS256 with Secret, or RS256 with the PEM public
+	// key at PublicKeyPath.
+	Algorithm     string
+	Secret        string
+	PublicKeyPath string
+	Issuer        string
+
+	// ServiceSecret signs the HS256 service tokens the services call each
+	// other with.
+	ServiceSecret string
+}
+
+// Verifier checks the tokens forwarded in the authorization metadata.
+type Verifier struct {
+	method     string
+	key        interface{}
+	issuer     string
+	serviceKey []byte
+}
+
+func NewVerifier(cfg VerifierConfig) (*Verifier, error) {
+	v := &Verifier{
+		method: cfg.Algorithm,
+		issuer: cfg.Issuer,
+	}
+
+	switch cfg.Algorithm {
+	case "HS256":
+		if len(cfg.Secret) < MinSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET must be set to at least %d bytes for HS256", MinSecretLength)
+		}
+		v.key = []byte(cfg.Secret)
+	case "RS256":
+		pem, err := os.ReadFile(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT public key: %v", err)
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT public key: %v", err)
+		}
+		v.key = key
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	if len(cfg.ServiceSecret) < MinSecretLength {
+		return nil, fmt.Errorf("SERVICE_TOKEN_SECRET must be set to at least %d bytes", MinSecretLength)
+	}
+	v.serviceKey = []byte(cfg.ServiceSecret)
+
+	return v, nil
+}
+
+// Verify checks the token's signature, algorithm, issuer and expiry and
+// returns the identity it was issued to. Only service tokens may carry
+// RoleService.
+func (v *Verifier) Verify(tokenString string) (Identity, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(tokenString, "Bearer "), claims, v.keyFor,
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return Identity{}, err
+	}
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("token has no subject")
+	}
+	return Identity{UserID: claims.Subject, Role: Role(claims.Role)}, nil
+}
+
+// keyFor picks the key by the token's issuer. The claims are not verified
+// yet at this point, but they are once the signature is checked against the
+// key returned here.
+func (v *Verifier) keyFor(token *jwt.Token) (interface{}, error) {
+	claims := token.Claims.(*Claims)
+	switch claims.Issuer {
+	case v.issuer:
+		if token.Method.Alg() != v.method {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+		}
+		if Role(claims.Role) == RoleService {
+			return nil, fmt.Errorf("access tokens may not carry role %q", RoleService)
+		}
+		return v.key, nil
+	case ServiceIssuer:
+		if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+		}
+		if Role(claims.Role) != RoleService {
+			return nil, fmt.Errorf("service tokens must carry role %q", RoleService)
+		}
+		return v.serviceKey, nil
+	default:
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+}
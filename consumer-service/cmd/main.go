@@ -2,32 +2,57 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	"authz"
+	"consumer-service/internal/config"
+	"consumer-service/internal/idempotency"
+	"consumer-service/internal/metrics"
+	"consumer-service/internal/processor"
+	"consumer-service/internal/saga"
+	"consumer-service/internal/stream"
 	pb "consumer-service/proto"
 	pborder "consumer-service/proto"
+	"events"
 )
 
 func main() {
+	cfg := config.NewConfig()
+
 	// Connect to NATS
-	nc, err := nats.Connect(nats.DefaultURL)
+	nc, err := nats.Connect(cfg.NATSURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to NATS: %v", err)
 	}
 	defer nc.Close()
 
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Fatalf("Failed to create JetStream context: %v", err)
+	}
+
+	// Calls to the other services are signed with a service token
+	serviceCreds, err := authz.NewServiceCredentials("consumer-service", cfg.ServiceTokenSecret)
+	if err != nil {
+		log.Fatalf("Failed to set up service credentials: %v", err)
+	}
+
 	// Connect to Inventory Service
-	inventoryConn, err := grpc.Dial("localhost:8080", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	inventoryConn, err := grpc.Dial(cfg.InventoryServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(serviceCreds),
+	)
 	if err != nil {
 		log.Fatalf("Failed to connect to inventory service: %v", err)
 	}
@@ -35,75 +60,85 @@ func main() {
 	inventoryClient := pb.NewInventoryServiceClient(inventoryConn)
 
 	// Connect to Order Service
-	orderConn, err := grpc.Dial("localhost:8081", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	orderConn, err := grpc.Dial(cfg.OrderServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(serviceCreds),
+	)
 	if err != nil {
 		log.Fatalf("Failed to connect to order service: %v", err)
 	}
 	defer orderConn.Close()
 	orderClient := pborder.NewOrderServiceClient(orderConn)
 
-	// Subscribe to order.created events
-	_, err = nc.Subscribe("order.created", func(msg *nats.Msg) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+	setupCtx, setupCancel := context.WithTimeout(context.Background(), time.Minute)
+	defer setupCancel()
 
-		var order pborder.OrderResponse
-		if err := json.Unmarshal(msg.Data, &order); err != nil {
-			log.Printf("Failed to unmarshal order: %v", err)
-			return
-		}
+	// Connect to MongoDB, where order placement sagas are kept
+	client, err := mongo.Connect(setupCtx, options.Client().ApplyURI(cfg.MongoDBURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	if err := client.Ping(setupCtx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
 
-		log.Printf("Processing order %s with %d items", order.Id, len(order.Items))
-
-		// 1. Validate inventory
-		var insufficientItems []string
-		for _, item := range order.Items {
-			product, err := inventoryClient.GetProduct(ctx, &pb.GetProductRequest{Id: item.ProductId})
-			if err != nil {
-				log.Printf("Product %s not found: %v", item.ProductId, err)
-				insufficientItems = append(insufficientItems, item.ProductId)
-				continue
-			}
-			if product.Stock < item.Quantity {
-				log.Printf("Insufficient stock for %s (has %d, needs %d)",
-					item.ProductId, product.Stock, item.Quantity)
-				insufficientItems = append(insufficientItems, item.ProductId)
-			}
-		}
+	db := client.Database(cfg.MongoDBName)
+	if err := saga.EnsureIndexes(setupCtx, db); err != nil {
+		log.Fatalf("Failed to create saga indexes: %v", err)
+	}
+	if err := idempotency.EnsureIndexes(setupCtx, db); err != nil {
+		log.Fatalf("Failed to create processed event indexes: %v", err)
+	}
 
-		if len(insufficientItems) > 0 {
-			updateOrderStatus(orderClient, ctx, order.Id, "failed", "insufficient stock for: "+strings.Join(insufficientItems, ","))
-			return
-		}
+	// Resume or roll back the sagas a previous run left unfinished
+	sagas := saga.NewOrchestrator(saga.NewRepository(db), inventoryClient, orderClient, cfg.SagaStaleAfter)
+	if err := sagas.Recover(setupCtx); err != nil {
+		log.Printf("Failed to recover unfinished sagas: %v", err)
+	}
 
-		// 2. Update inventory atomically
-		for _, item := range order.Items {
-			product, err := inventoryClient.GetProduct(ctx, &pb.GetProductRequest{Id: item.ProductId})
-			if err != nil || product.Stock < item.Quantity {
-				log.Printf("Race condition or missing product: %s", item.ProductId)
-				updateOrderStatus(orderClient, ctx, order.Id, "failed", "inventory conflict for "+item.ProductId)
-				return
-			}
-
-			_, err = inventoryClient.UpdateProduct(ctx, &pb.ProductRequest{
-				Id:    item.ProductId,
-				Stock: product.Stock - item.Quantity,
-			})
-			if err != nil {
-				log.Printf("Failed to update product %s: %v", item.ProductId, err)
-				updateOrderStatus(orderClient, ctx, order.Id, "failed", "update failed for "+item.ProductId)
-				return
-			}
-			log.Printf("Updated stock for %s to %d", item.ProductId, product.Stock-item.Quantity)
-		}
+	processed := idempotency.NewStore(db, cfg.ProcessedEventTTL, cfg.AckWait)
+	orderProcessor := processor.NewOrderProcessor(sagas, processed)
 
-		// 3. Finalize order
-		updateOrderStatus(orderClient, ctx, order.Id, "completed", "")
-		log.Printf("Order %s completed successfully", order.Id)
-	})
+	metrics.Serve(cfg.MetricsAddr)
 
-	if err != nil {
-		log.Fatalf("Failed to subscribe to NATS: %v", err)
+	// Consume order events through durable consumers, so events published
+	// while this service is down are delivered once it is back
+	if err := events.EnsureStream(setupCtx, js, cfg.StreamName, cfg.StreamSubjects); err != nil {
+		log.Fatalf("Failed to set up %s stream: %v", cfg.StreamName, err)
+	}
+	consumers := []struct {
+		durable, subject, deadLetter string
+		handler                      stream.Handler
+	}{
+		{cfg.ConsumerName, cfg.OrderSubject, cfg.DeadLetterSubject, orderProcessor.HandleOrderCreated},
+		{cfg.CancelConsumerName, cfg.CancelSubject, cfg.CancelDeadLetterSubject, orderProcessor.HandleOrderCancelled},
+	}
+
+	var running []*stream.Consumer
+	for _, c := range consumers {
+		consumer, err := stream.NewConsumer(setupCtx, js, stream.ConsumerConfig{
+			Stream:        cfg.StreamName,
+			Durable:       c.durable,
+			FilterSubject: c.subject,
+			AckWait:       cfg.AckWait,
+			MaxDeliver:    cfg.MaxDeliver,
+			Backoff:       cfg.Backoff,
+
+			DeadLetterSubject: c.deadLetter,
+
+			Workers:     cfg.Workers,
+			QueueSize:   cfg.WorkerQueueSize,
+			MaxInFlight: cfg.MaxInFlight,
+			Key:         processor.OrderKey,
+		}, c.handler)
+		if err != nil {
+			log.Fatalf("Failed to create %s consumer: %v", c.durable, err)
+		}
+		if err := consumer.Start(); err != nil {
+			log.Fatalf("Failed to consume %s: %v", c.subject, err)
+		}
+		running = append(running, consumer)
 	}
 
 	// Graceful shutdown
@@ -113,14 +148,13 @@ func main() {
 	log.Println("Consumer service running. Waiting for events...")
 	<-quit
 	log.Println("Shutting down consumer service...")
-}
 
-func updateOrderStatus(client pborder.OrderServiceClient, ctx context.Context, orderID, status, reason string) {
-	_, err := client.UpdateOrderStatus(ctx, &pborder.UpdateOrderStatusRequest{
-		Id:     orderID,
-		Status: status,
-	})
-	if err != nil {
-		log.Printf("Failed to update order %s status to %s: %v", orderID, status, err)
+	// Let the workers finish the orders they already hold
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer shutdownCancel()
+	for _, consumer := range running {
+		if err := consumer.Stop(shutdownCtx); err != nil {
+			log.Printf("In-flight orders did not finish before shutdown: %v", err)
+		}
 	}
 }
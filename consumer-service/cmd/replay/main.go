@@ -0,0 +1,251 @@
+// Command replay lists, inspects, republishes and purges order events that
+// consumer-service moved to the dead-letter subject.
+//
+//	replay list      [-cancelled] [-order ID] [-since TIME] [-until TIME]
+//	replay inspect   [-cancelled] -seq N
+//	replay republish [-cancelled] (-seq N | -order ID | -since TIME | -until TIME | -all)
+//	replay purge     [-cancelled] (-seq N | -order ID | -since TIME | -until TIME | -all)
+//
+// Times are RFC 3339, e.g. 2024-05-01T12:00:00Z, and refer to when the event
+// was dead-lettered. -cancelled works on dead-lettered order.cancelled events
+// instead of order.created ones.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"consumer-service/internal/config"
+	"consumer-service/internal/stream"
+	"events"
+)
+
+type options struct {
+	seq     uint64
+	orderID string
+	since   time.Time
+	until   time.Time
+	all     bool
+	// deadLetterSubject is the dead-letter subject worked on.
+	deadLetterSubject string
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command := os.Args[1]
+
+	cfg := config.NewConfig()
+	opts, err := parseOptions(cfg, command, os.Args[2:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to NATS: %v", err)
+	}
+	defer nc.Close()
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Fatalf("Failed to create JetStream context: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch command {
+	case "list":
+		err = list(ctx, js, cfg, opts)
+	case "inspect":
+		err = inspect(ctx, js, cfg, opts)
+	case "republish":
+		err = apply(ctx, js, cfg, opts, "Republished", func(letter *stream.DeadLetter) error {
+			return stream.Republish(ctx, js, cfg.StreamName, letter)
+		})
+	case "purge":
+		err = apply(ctx, js, cfg, opts, "Purged", func(letter *stream.DeadLetter) error {
+			return stream.Purge(ctx, js, cfg.StreamName, letter.Sequence)
+		})
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: replay list|inspect|republish|purge [flags]")
+	os.Exit(2)
+}
+
+func parseOptions(cfg *config.Config, command string, args []string) (*options, error) {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	seq := fs.Uint64("seq", 0, "stream sequence of a single dead letter")
+	orderID := fs.String("order", "", "only dead letters for this order ID")
+	since := fs.String("since", "", "only dead letters from this time on (RFC 3339)")
+	until := fs.String("until", "", "only dead letters up to this time (RFC 3339)")
+	all := fs.Bool("all", false, "apply to every dead letter")
+	cancelled := fs.Bool("cancelled", false, "work on dead-lettered order.cancelled events")
+	fs.Parse(args)
+
+	opts := &options{seq: *seq, orderID: *orderID, all: *all, deadLetterSubject: cfg.DeadLetterSubject}
+	if *cancelled {
+		opts.deadLetterSubject = cfg.CancelDeadLetterSubject
+	}
+	var err error
+	if *since != "" {
+		if opts.since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return nil, fmt.Errorf("invalid -since: %v", err)
+		}
+	}
+	if *until != "" {
+		if opts.until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return nil, fmt.Errorf("invalid -until: %v", err)
+		}
+	}
+
+	switch command {
+	case "list":
+	case "inspect":
+		if opts.seq == 0 {
+			return nil, errors.New("inspect needs -seq")
+		}
+	case "republish", "purge":
+		filtered := opts.orderID != "" || !opts.since.IsZero() || !opts.until.IsZero()
+		if opts.seq == 0 && !filtered && !opts.all {
+			return nil, fmt.Errorf("%s needs -seq, a filter or -all", command)
+		}
+	default:
+		usage()
+	}
+	return opts, nil
+}
+
+// selectLetters returns the dead letters opts refers to.
+func selectLetters(ctx context.Context, js jetstream.JetStream, cfg *config.Config, opts *options) ([]stream.DeadLetter, error) {
+	if opts.seq != 0 {
+		letter, err := stream.GetDeadLetter(ctx, js, cfg.StreamName, opts.deadLetterSubject, opts.seq)
+		if err != nil {
+			return nil, fmt.Errorf("dead letter %d: %w", opts.seq, err)
+		}
+		return []stream.DeadLetter{*letter}, nil
+	}
+
+	letters, err := stream.ListDeadLetters(ctx, js, cfg.StreamName, opts.deadLetterSubject, opts.since, opts.until)
+	if err != nil {
+		return nil, err
+	}
+	if opts.orderID == "" {
+		return letters, nil
+	}
+
+	var matched []stream.DeadLetter
+	for _, letter := range letters {
+		if orderID(letter.Data) == opts.orderID {
+			matched = append(matched, letter)
+		}
+	}
+	return matched, nil
+}
+
+func list(ctx context.Context, js jetstream.JetStream, cfg *config.Config, opts *options) error {
+	letters, err := selectLetters(ctx, js, cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tDEAD-LETTERED\tSUBJECT\tORDER\tATTEMPTS\tERROR")
+	for _, letter := range letters {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n",
+			letter.Sequence,
+			letter.Time.UTC().Format(time.RFC3339),
+			letter.OriginalSubject,
+			orderID(letter.Data),
+			letter.Attempts,
+			letter.Error,
+		)
+	}
+	return w.Flush()
+}
+
+func inspect(ctx context.Context, js jetstream.JetStream, cfg *config.Config, opts *options) error {
+	letters, err := selectLetters(ctx, js, cfg, opts)
+	if err != nil {
+		return err
+	}
+	letter := letters[0]
+
+	fmt.Printf("Sequence:      %d\n", letter.Sequence)
+	fmt.Printf("Dead-lettered: %s\n", letter.Time.UTC().Format(time.RFC3339))
+	fmt.Printf("Subject:       %s\n", letter.OriginalSubject)
+	fmt.Printf("Order:         %s\n", orderID(letter.Data))
+	fmt.Printf("Attempts:      %d\n", letter.Attempts)
+	fmt.Printf("Error:         %s\n", letter.Error)
+
+	fmt.Println("\nHeaders:")
+	keys := make([]string, 0, len(letter.Header))
+	for key := range letter.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range letter.Header[key] {
+			fmt.Printf("  %s: %s\n", key, value)
+		}
+	}
+
+	fmt.Println("\nPayload:")
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, letter.Data, "  ", "  "); err == nil {
+		fmt.Printf("  %s\n", pretty.String())
+	} else {
+		fmt.Printf("  %q\n", letter.Data)
+	}
+	return nil
+}
+
+func apply(ctx context.Context, js jetstream.JetStream, cfg *config.Config, opts *options, verb string, fn func(*stream.DeadLetter) error) error {
+	letters, err := selectLetters(ctx, js, cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for i := range letters {
+		if err := fn(&letters[i]); err != nil {
+			log.Printf("Dead letter %d: %v", letters[i].Sequence, err)
+			continue
+		}
+		done++
+	}
+	fmt.Printf("%s %d of %d dead letters\n", verb, done, len(letters))
+	if done < len(letters) {
+		return errors.New("some dead letters could not be processed")
+	}
+	return nil
+}
+
+// orderID returns the order an event envelope is about, if it decodes.
+func orderID(data []byte) string {
+	env, err := events.Parse(data)
+	if err != nil {
+		return ""
+	}
+	return env.Subject
+}
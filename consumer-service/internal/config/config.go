@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	NATSURL              string
+	InventoryServiceAddr string
+	OrderServiceAddr     string
+	// ServiceTokenSecret signs the service tokens this service calls
+	// inventory-service and order-service with.
+	ServiceTokenSecret string
+
+	MongoDBURI  string
+	MongoDBName string
+	// SagaStaleAfter is how long an unfinished saga may sit before it is
+	// rolled back on startup instead of resumed.
+	SagaStaleAfter time.Duration
+	// ProcessedEventTTL is how long processed event IDs are remembered for
+	// duplicate detection.
+	ProcessedEventTTL time.Duration
+
+	// MetricsAddr is where counters are served at /debug/vars.
+	MetricsAddr string
+
+	StreamName     string
+	StreamSubjects []string
+	ConsumerName   string
+	OrderSubject   string
+	// DeadLetterSubject receives order events that could not be processed.
+	DeadLetterSubject string
+
+	// Cancellations are consumed by a durable consumer of their own, so a
+	// backlog of new orders does not hold back giving stock back.
+	CancelConsumerName      string
+	CancelSubject           string
+	CancelDeadLetterSubject string
+
+	// AckWait is how long JetStream waits for an ack before redelivering.
+	AckWait time.Duration
+	// MaxDeliver caps the delivery attempts of a single message before it is
+	// moved to the dead-letter subject.
+	MaxDeliver int
+	// Backoff is the delay before each redelivery of a failed message; the
+	// last entry is reused once the list runs out.
+	Backoff []time.Duration
+
+	// Workers is the number of orders processed concurrently. Events of one
+	// order are always processed in order by the same worker.
+	Workers         int
+	WorkerQueueSize int
+	// MaxInFlight caps the messages pulled but not yet acked.
+	MaxInFlight int
+	// ShutdownTimeout bounds how long in-flight work may take to drain.
+	ShutdownTimeout time.Duration
+}
+
+func NewConfig() *Config {
+	return &Config{
+		NATSURL:              getEnv("NATS_URL", "nats://127.0.0.1:4222"),
+		InventoryServiceAddr: getEnv("INVENTORY_SERVICE_ADDR", "localhost:8080"),
+		OrderServiceAddr:     getEnv("ORDER_SERVICE_ADDR", "localhost:8081"),
+		ServiceTokenSecret:   getEnv("SERVICE_TOKEN_SECRET", ""),
+
+		MongoDBURI:     getEnv("CONSUMER_DB_URI", "mongodb://localhost:27017"),
+		MongoDBName:    getEnv("CONSUMER_DB_NAME", "consumer_db"),
+		SagaStaleAfter: getEnvDuration("SAGA_STALE_AFTER", 10*time.Minute),
+
+		ProcessedEventTTL: getEnvDuration("PROCESSED_EVENT_TTL", 7*24*time.Hour),
+
+		MetricsAddr: getEnv("METRICS_ADDR", ":9102"),
+
+		StreamName:     getEnv("ORDERS_STREAM", "ORDERS"),
+		StreamSubjects: []string{"order.>"},
+		ConsumerName:   getEnv("ORDERS_CONSUMER", "consumer-service"),
+		OrderSubject:   "order.created",
+
+		DeadLetterSubject: "order.created.dlq",
+
+		CancelConsumerName:      getEnv("ORDERS_CANCEL_CONSUMER", "consumer-service-cancellations"),
+		CancelSubject:           "order.cancelled",
+		CancelDeadLetterSubject: "order.cancelled.dlq",
+
+		AckWait:    getEnvDuration("ACK_WAIT", 30*time.Second),
+		MaxDeliver: getEnvInt("MAX_DELIVER", 5),
+		Backoff:    []time.Duration{time.Second, 5 * time.Second, 30 * time.Second, 2 * time.Minute},
+
+		Workers:         getEnvInt("WORKERS", 8),
+		WorkerQueueSize: getEnvInt("WORKER_QUEUE_SIZE", 16),
+		MaxInFlight:     getEnvInt("MAX_IN_FLIGHT", 128),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,140 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type Status string
+
+const (
+	StatusProcessing Status = "processing"
+	StatusDone       Status = "done"
+)
+
+// ErrInProgress means another worker is processing the event right now.
+var ErrInProgress = errors.New("event is being processed")
+
+// Record is what is remembered about a processed event. MongoDB removes it
+// once ExpiresAt has passed.
+type Record struct {
+	EventID   string `bson:"_id"`
+	EventType string `bson:"event_type"`
+	Status    Status `bson:"status"`
+	// Outcome and Error are the result the event was processed with, which
+	// duplicates of it get back.
+	Outcome     string    `bson:"outcome,omitempty"`
+	Error       string    `bson:"error,omitempty"`
+	ClaimedAt   int64     `bson:"claimed_at"`
+	ProcessedAt int64     `bson:"processed_at,omitempty"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+// Store records which events have been processed, so redelivered and
+// duplicated events are skipped.
+type Store interface {
+	// Claim marks an event as being processed by the caller. It returns the
+	// stored record when the event was already processed, and ErrInProgress
+	// when another worker holds a claim that has not lapsed.
+	Claim(ctx context.Context, eventID, eventType string) (*Record, error)
+	// Complete stores the result of a claimed event.
+	Complete(ctx context.Context, eventID, outcome, errMsg string) error
+	// Release gives up a claim so the event can be processed again.
+	Release(ctx context.Context, eventID string) error
+}
+
+type mongoStore struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+	lease      time.Duration
+}
+
+// NewStore keeps processed events for ttl. A claim that is not completed
+// within lease, e.g. because its worker crashed, may be taken over.
+func NewStore(db *mongo.Database, ttl, lease time.Duration) Store {
+	return &mongoStore{
+		collection: db.Collection("processed_events"),
+		ttl:        ttl,
+		lease:      lease,
+	}
+}
+
+// EnsureIndexes creates the TTL index that expires processed events.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("processed_events").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+func (s *mongoStore) Claim(ctx context.Context, eventID, eventType string) (*Record, error) {
+	now := time.Now()
+
+	// The unique _id makes the first insert win, so only one worker can
+	// claim an event
+	_, err := s.collection.InsertOne(ctx, Record{
+		EventID:   eventID,
+		EventType: eventType,
+		Status:    StatusProcessing,
+		ClaimedAt: now.Unix(),
+		ExpiresAt: now.Add(s.ttl),
+	})
+	if err == nil {
+		return nil, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, err
+	}
+
+	// Take over a claim whose worker did not finish in time
+	res, err := s.collection.UpdateOne(ctx,
+		bson.M{
+			"_id":        eventID,
+			"status":     StatusProcessing,
+			"claimed_at": bson.M{"$lt": now.Add(-s.lease).Unix()},
+		},
+		bson.M{"$set": bson.M{"claimed_at": now.Unix()}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.ModifiedCount == 1 {
+		return nil, nil
+	}
+
+	var record Record
+	if err := s.collection.FindOne(ctx, bson.M{"_id": eventID}).Decode(&record); err != nil {
+		if err == mongo.ErrNoDocuments {
+			// Released or expired in the meantime
+			return nil, ErrInProgress
+		}
+		return nil, err
+	}
+	if record.Status != StatusDone {
+		return nil, ErrInProgress
+	}
+	return &record, nil
+}
+
+func (s *mongoStore) Complete(ctx context.Context, eventID, outcome, errMsg string) error {
+	now := time.Now()
+	_, err := s.collection.UpdateOne(ctx, bson.M{"_id": eventID}, bson.M{"$set": bson.M{
+		"status":       StatusDone,
+		"outcome":      outcome,
+		"error":        errMsg,
+		"processed_at": now.Unix(),
+		"expires_at":   now.Add(s.ttl),
+	}})
+	return err
+}
+
+func (s *mongoStore) Release(ctx context.Context, eventID string) error {
+	_, err := s.collection.DeleteOne(ctx, bson.M{"_id": eventID, "status": StatusProcessing})
+	return err
+}
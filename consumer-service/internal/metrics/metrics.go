@@ -0,0 +1,27 @@
+// Package metrics exposes consumer-service counters over HTTP in expvar's
+// JSON format at /debug/vars.
+package metrics
+
+import (
+	"expvar"
+	"log"
+	"net/http"
+)
+
+var (
+	EventsProcessed    = expvar.NewInt("events_processed")
+	DuplicatesSkipped  = expvar.NewInt("events_duplicates_skipped")
+	EventsRetried      = expvar.NewInt("events_retried")
+	EventsDeadLettered = expvar.NewInt("events_dead_lettered")
+)
+
+// Serve starts the metrics endpoint on addr in the background.
+func Serve(addr string) {
+	go func() {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Printf("Metrics server stopped: %v", err)
+		}
+	}()
+}
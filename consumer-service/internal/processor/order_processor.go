@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"consumer-service/internal/idempotency"
+	"consumer-service/internal/metrics"
+	"consumer-service/internal/saga"
+	"consumer-service/internal/stream"
+	"events"
+	eventspb "events/proto"
+)
+
+// OrderProcessor turns order.created events into order placement sagas and
+// order.cancelled events into their compensation.
+//
+// Every event ID is claimed in the processed-event store before any work is
+// done, so a duplicated or redelivered event gets the stored result back
+// instead of running again. Should an event still be processed twice, e.g.
+// after its claim expired, the saga saved for the order records how far its
+// placement got and is resumed rather than restarted.
+type OrderProcessor struct {
+	sagas     *saga.Orchestrator
+	processed idempotency.Store
+}
+
+func NewOrderProcessor(sagas *saga.Orchestrator, processed idempotency.Store) *OrderProcessor {
+	return &OrderProcessor{sagas: sagas, processed: processed}
+}
+
+// HandleOrderCreated processes an order.created event. Returning an error
+// makes the message be redelivered, unless it wraps stream.ErrPermanent.
+func (p *OrderProcessor) HandleOrderCreated(ctx context.Context, data []byte) error {
+	// Unknown schema versions and malformed envelopes will not decode on
+	// any later attempt either
+	var event eventspb.OrderCreated
+	env, err := events.Decode(data, &event)
+	if err != nil {
+		return fmt.Errorf("%w: decode order.created: %v", stream.ErrPermanent, err)
+	}
+	if event.OrderId == "" {
+		return fmt.Errorf("%w: event %s has no order id", stream.ErrPermanent, env.ID)
+	}
+
+	return p.process(ctx, env, event.OrderId, func() (saga.State, error) {
+		log.Printf("Processing order %s with %d items (event %s, correlation %s)", event.OrderId, len(event.Items), env.ID, env.CorrelationID)
+		return p.sagas.PlaceOrder(ctx, event.OrderId)
+	})
+}
+
+// HandleOrderCancelled processes an order.cancelled event by giving back the
+// stock held for the order.
+func (p *OrderProcessor) HandleOrderCancelled(ctx context.Context, data []byte) error {
+	var event eventspb.OrderCancelled
+	env, err := events.Decode(data, &event)
+	if err != nil {
+		return fmt.Errorf("%w: decode order.cancelled: %v", stream.ErrPermanent, err)
+	}
+	if event.OrderId == "" {
+		return fmt.Errorf("%w: event %s has no order id", stream.ErrPermanent, env.ID)
+	}
+
+	return p.process(ctx, env, event.OrderId, func() (saga.State, error) {
+		log.Printf("Cancelling order %s from %s: %s (event %s, correlation %s)", event.OrderId, event.FromStatus, event.Reason, env.ID, env.CorrelationID)
+		return p.sagas.CancelOrder(ctx, event.OrderId, event.Reason)
+	})
+}
+
+// process runs an event through the processed-event store: a duplicate gets
+// the stored result back, a transient failure frees the event for the
+// redelivery and any other result is recorded.
+func (p *OrderProcessor) process(ctx context.Context, env *events.Envelope, orderID string, run func() (saga.State, error)) error {
+	record, err := p.processed.Claim(ctx, env.ID, env.Type)
+	if err != nil {
+		return fmt.Errorf("claim event %s: %w", env.ID, err)
+	}
+	if record != nil {
+		metrics.DuplicatesSkipped.Add(1)
+		log.Printf("Skipping duplicate event %s for order %s, processed with outcome %q", env.ID, orderID, record.Outcome)
+		if record.Error != "" {
+			return fmt.Errorf("%w: %s", stream.ErrPermanent, record.Error)
+		}
+		return nil
+	}
+
+	state, err := run()
+	if err != nil && !errors.Is(err, stream.ErrPermanent) {
+		// Let the redelivery try again
+		if releaseErr := p.processed.Release(ctx, env.ID); releaseErr != nil {
+			log.Printf("Failed to release event %s: %v", env.ID, releaseErr)
+		}
+		return err
+	}
+
+	outcome, errMsg := string(state), ""
+	if outcome == "" {
+		outcome = "skipped"
+	}
+	if err != nil {
+		outcome, errMsg = "failed", err.Error()
+	}
+	if completeErr := p.processed.Complete(ctx, env.ID, outcome, errMsg); completeErr != nil {
+		log.Printf("Failed to record event %s as processed: %v", env.ID, completeErr)
+	}
+	metrics.EventsProcessed.Add(1)
+	return err
+}
+
+// OrderKey returns the order an event is about, so events of one order are
+// processed in sequence.
+func OrderKey(data []byte) string {
+	env, err := events.Parse(data)
+	if err != nil {
+		return ""
+	}
+	return env.Subject
+}
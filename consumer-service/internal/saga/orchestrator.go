@@ -0,0 +1,318 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "consumer-service/proto"
+)
+
+// Orchestrator drives order placement sagas:
+//
+//	reserve every item -> confirm the order -> commit the reservations
+//
+// A business failure at any step (insufficient stock, an order that was
+// cancelled meanwhile, an expired reservation) compensates by giving back the
+// stock taken so far and marking the order failed. Cancelling the order
+// compensates the same way, even once the saga has completed. Transient
+// failures are returned to the caller and the saga stays at its step, ready
+// to be resumed.
+type Orchestrator struct {
+	repo       Repository
+	inventory  pb.InventoryServiceClient
+	orders     pb.OrderServiceClient
+	staleAfter time.Duration
+}
+
+func NewOrchestrator(
+	repo Repository,
+	inventory pb.InventoryServiceClient,
+	orders pb.OrderServiceClient,
+	staleAfter time.Duration,
+) *Orchestrator {
+	return &Orchestrator{
+		repo:       repo,
+		inventory:  inventory,
+		orders:     orders,
+		staleAfter: staleAfter,
+	}
+}
+
+// PlaceOrder runs the saga for an order, starting it if the order is still
+// pending and resuming it if it already exists, and returns the state it
+// finished in. The state is empty when the order needed no saga. It is safe
+// to call again for the same order.
+func (o *Orchestrator) PlaceOrder(ctx context.Context, orderID string) (State, error) {
+	s, err := o.repo.FindByID(ctx, orderID)
+	if errors.Is(err, ErrSagaNotFound) {
+		s, err = o.start(ctx, orderID)
+	}
+	if err != nil || s == nil {
+		return "", err
+	}
+	if err := o.run(ctx, s); err != nil {
+		return "", err
+	}
+	return s.State, nil
+}
+
+// CancelOrder gives back the stock held for a cancelled order by
+// compensating its saga, and returns the state the saga finished in. The
+// state is empty when the order never got a saga, which means no stock was
+// taken for it. It is safe to call again for the same order.
+func (o *Orchestrator) CancelOrder(ctx context.Context, orderID, reason string) (State, error) {
+	s, err := o.repo.FindByID(ctx, orderID)
+	if errors.Is(err, ErrSagaNotFound) {
+		log.Printf("Order %s has no saga, no stock to give back", orderID)
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if s.State != StateCompensating && s.State != StateCompensated {
+		if reason == "" {
+			reason = "order cancelled"
+		}
+		if err := o.compensate(ctx, s, reason); err != nil {
+			return "", err
+		}
+	}
+	if err := o.run(ctx, s); err != nil {
+		return "", err
+	}
+	return s.State, nil
+}
+
+// Recover finishes the sagas a previous run left behind. Sagas still short
+// of confirming the order are rolled back once they are older than the
+// stale limit, since their reservations may be about to expire; all others
+// are resumed.
+func (o *Orchestrator) Recover(ctx context.Context) error {
+	sagas, err := o.repo.FindUnfinished(ctx)
+	if err != nil {
+		return err
+	}
+
+	staleBefore := time.Now().Add(-o.staleAfter).Unix()
+	for i := range sagas {
+		s := &sagas[i]
+		if (s.State == StateReserving || s.State == StateConfirming) && s.UpdatedAt < staleBefore {
+			log.Printf("Rolling back stale saga for order %s at %s", s.ID, s.State)
+			if err := o.compensate(ctx, s, "order placement did not finish in time"); err != nil {
+				log.Printf("Failed to roll back saga for order %s: %v", s.ID, err)
+				continue
+			}
+		} else {
+			log.Printf("Resuming saga for order %s at %s", s.ID, s.State)
+		}
+		if err := o.run(ctx, s); err != nil {
+			log.Printf("Failed to resume saga for order %s: %v", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// start creates the saga for a pending order. It returns nil without error
+// when the order is past the point where it needs one.
+func (o *Orchestrator) start(ctx context.Context, orderID string) (*Saga, error) {
+	order, err := o.orders.GetOrder(ctx, &pb.GetOrderRequest{Id: orderID})
+	if err != nil {
+		return nil, fmt.Errorf("get order %s: %w", orderID, err)
+	}
+	if order.Status != "pending" {
+		log.Printf("Order %s is already %s, nothing to do", order.Id, order.Status)
+		return nil, nil
+	}
+
+	s := &Saga{ID: order.Id, UserID: order.UserId, State: StateReserving}
+	for _, item := range order.Items {
+		s.Items = append(s.Items, Item{ProductID: item.ProductId, Quantity: item.Quantity})
+	}
+	if err := o.repo.Create(ctx, s); err != nil {
+		if errors.Is(err, ErrSagaExists) {
+			return o.repo.FindByID(ctx, orderID)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (o *Orchestrator) run(ctx context.Context, s *Saga) error {
+	for !s.State.Finished() {
+		var err error
+		switch s.State {
+		case StateReserving:
+			err = o.reserve(ctx, s)
+		case StateConfirming:
+			err = o.confirm(ctx, s)
+		case StateCommitting:
+			err = o.commit(ctx, s)
+		case StateCompensating:
+			err = o.rollback(ctx, s)
+		default:
+			err = fmt.Errorf("unknown saga state %q", s.State)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.State == StateCompleted {
+		log.Printf("Order %s reserved successfully", s.ID)
+	} else {
+		log.Printf("Order %s rolled back: %s", s.ID, s.Reason)
+	}
+	return nil
+}
+
+// reserve takes stock for every item that does not hold a reservation yet.
+// The saga is saved after each one, so no reservation is ever forgotten.
+func (o *Orchestrator) reserve(ctx context.Context, s *Saga) error {
+	for i := range s.Items {
+		item := &s.Items[i]
+		if item.ReservationID != "" {
+			continue
+		}
+
+		res, err := o.inventory.ReserveStock(ctx, &pb.ReserveRequest{
+			ProductId: item.ProductID,
+			Quantity:  item.Quantity,
+			OrderId:   s.ID,
+		})
+		if err != nil {
+			if retryable(err) {
+				return fmt.Errorf("reserve %s: %w", item.ProductID, err)
+			}
+			return o.compensate(ctx, s, "could not reserve "+item.ProductID+": "+status.Convert(err).Message())
+		}
+
+		item.ReservationID = res.ReservationId
+		if err := o.repo.Save(ctx, s); err != nil {
+			return err
+		}
+	}
+	return o.advance(ctx, s, StateConfirming)
+}
+
+// confirm moves the order to reserved now that all of its stock is held.
+func (o *Orchestrator) confirm(ctx context.Context, s *Saga) error {
+	order, err := o.orders.GetOrder(ctx, &pb.GetOrderRequest{Id: s.ID})
+	if err != nil {
+		return fmt.Errorf("get order %s: %w", s.ID, err)
+	}
+
+	switch order.Status {
+	case "pending":
+		_, err := o.orders.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+			Id:     s.ID,
+			Status: "reserved",
+			Reason: "stock reserved for all items",
+		})
+		if err != nil {
+			if retryable(err) {
+				return fmt.Errorf("confirm order %s: %w", s.ID, err)
+			}
+			return o.compensate(ctx, s, "could not confirm order: "+status.Convert(err).Message())
+		}
+	case "reserved":
+		// Confirmed before a crash
+	default:
+		return o.compensate(ctx, s, "order is "+order.Status)
+	}
+	return o.advance(ctx, s, StateCommitting)
+}
+
+// commit makes the stock decrement of every reservation permanent.
+func (o *Orchestrator) commit(ctx context.Context, s *Saga) error {
+	for i := range s.Items {
+		item := &s.Items[i]
+		if item.Committed {
+			continue
+		}
+
+		_, err := o.inventory.CommitReservation(ctx, &pb.CommitReservationRequest{ReservationId: item.ReservationID})
+		if err != nil {
+			if retryable(err) {
+				return fmt.Errorf("commit reservation %s: %w", item.ReservationID, err)
+			}
+			return o.compensate(ctx, s, "commit failed for reservation "+item.ReservationID)
+		}
+
+		item.Committed = true
+		if err := o.repo.Save(ctx, s); err != nil {
+			return err
+		}
+	}
+	return o.advance(ctx, s, StateCompleted)
+}
+
+// compensate switches the saga to rolling back for the given reason.
+func (o *Orchestrator) compensate(ctx context.Context, s *Saga, reason string) error {
+	log.Printf("Compensating order %s: %s", s.ID, reason)
+	s.Reason = reason
+	return o.advance(ctx, s, StateCompensating)
+}
+
+// rollback releases every reservation that was not committed, returns the
+// stock of those that were, and marks the order failed unless it has already
+// been cancelled. Inventory-service treats both as no-ops when repeated.
+func (o *Orchestrator) rollback(ctx context.Context, s *Saga) error {
+	for _, item := range s.Items {
+		if item.ReservationID == "" {
+			continue
+		}
+
+		var err error
+		if item.Committed {
+			_, err = o.inventory.ReturnStock(ctx, &pb.ReturnStockRequest{ReservationId: item.ReservationID})
+		} else {
+			_, err = o.inventory.ReleaseStock(ctx, &pb.ReleaseRequest{ReservationId: item.ReservationID})
+		}
+		if err != nil {
+			if retryable(err) {
+				return fmt.Errorf("give back reservation %s: %w", item.ReservationID, err)
+			}
+			log.Printf("Failed to give back reservation %s: %v", item.ReservationID, err)
+		}
+	}
+
+	order, err := o.orders.GetOrder(ctx, &pb.GetOrderRequest{Id: s.ID})
+	if err != nil {
+		return fmt.Errorf("get order %s: %w", s.ID, err)
+	}
+	if order.Status == "pending" || order.Status == "reserved" {
+		_, err := o.orders.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+			Id:     s.ID,
+			Status: "failed",
+			Reason: s.Reason,
+		})
+		if err != nil && retryable(err) {
+			return fmt.Errorf("fail order %s: %w", s.ID, err)
+		}
+	}
+	return o.advance(ctx, s, StateCompensated)
+}
+
+func (o *Orchestrator) advance(ctx context.Context, s *Saga, state State) error {
+	s.State = state
+	return o.repo.Save(ctx, s)
+}
+
+// retryable reports whether a gRPC error is transient, as opposed to a
+// business outcome such as insufficient stock that a retry would repeat.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition,
+		codes.PermissionDenied, codes.Unauthenticated:
+		return false
+	default:
+		return true
+	}
+}
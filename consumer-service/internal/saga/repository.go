@@ -0,0 +1,91 @@
+package saga
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Repository interface {
+	Create(ctx context.Context, saga *Saga) error
+	FindByID(ctx context.Context, id string) (*Saga, error)
+	Save(ctx context.Context, saga *Saga) error
+	FindUnfinished(ctx context.Context) ([]Saga, error)
+}
+
+type mongoRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) Repository {
+	return &mongoRepository{collection: db.Collection("sagas")}
+}
+
+// EnsureIndexes creates the index used to find unfinished sagas on startup.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("sagas").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "state", Value: 1}},
+	})
+	return err
+}
+
+func (r *mongoRepository) Create(ctx context.Context, saga *Saga) error {
+	now := time.Now().Unix()
+	saga.CreatedAt = now
+	saga.UpdatedAt = now
+	saga.Version = 1
+
+	if _, err := r.collection.InsertOne(ctx, saga); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrSagaExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *mongoRepository) FindByID(ctx context.Context, id string) (*Saga, error) {
+	var saga Saga
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&saga)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrSagaNotFound
+		}
+		return nil, err
+	}
+	return &saga, nil
+}
+
+// Save replaces the stored saga as long as nobody else saved it since it was
+// read, and bumps its version.
+func (r *mongoRepository) Save(ctx context.Context, saga *Saga) error {
+	next := *saga
+	next.Version++
+	next.UpdatedAt = time.Now().Unix()
+
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": saga.ID, "version": saga.Version}, next)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrConflict
+	}
+	*saga = next
+	return nil
+}
+
+func (r *mongoRepository) FindUnfinished(ctx context.Context) ([]Saga, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"state": bson.M{"$nin": bson.A{StateCompleted, StateCompensated}}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sagas []Saga
+	if err := cursor.All(ctx, &sagas); err != nil {
+		return nil, err
+	}
+	return sagas, nil
+}
@@ -0,0 +1,52 @@
+package saga
+
+import "errors"
+
+// State is the step an order placement saga is at. A saga moves forward
+// through reserving, confirming and committing; any business failure on the
+// way sends it to compensating, which undoes what was done so far.
+type State string
+
+const (
+	StateReserving    State = "reserving"
+	StateConfirming   State = "confirming"
+	StateCommitting   State = "committing"
+	StateCompensating State = "compensating"
+	StateCompleted    State = "completed"
+	StateCompensated  State = "compensated"
+)
+
+// Finished reports whether the saga has nothing left to do.
+func (s State) Finished() bool {
+	return s == StateCompleted || s == StateCompensated
+}
+
+// Saga tracks the placement of one order. It is saved after every step, so
+// a restarted consumer knows exactly which reservations exist and can resume
+// or roll back.
+type Saga struct {
+	ID     string `bson:"_id"` // the order ID
+	UserID string `bson:"user_id"`
+	State  State  `bson:"state"`
+	Items  []Item `bson:"items"`
+	// Reason explains why the saga is being compensated.
+	Reason string `bson:"reason,omitempty"`
+	// Version is bumped on every save to detect concurrent runners.
+	Version   int64 `bson:"version"`
+	CreatedAt int64 `bson:"created_at"`
+	UpdatedAt int64 `bson:"updated_at"`
+}
+
+type Item struct {
+	ProductID     string `bson:"product_id"`
+	Quantity      int32  `bson:"quantity"`
+	ReservationID string `bson:"reservation_id,omitempty"`
+	Committed     bool   `bson:"committed,omitempty"`
+}
+
+var (
+	ErrSagaNotFound = errors.New("saga not found")
+	ErrSagaExists   = errors.New("saga already exists")
+	// ErrConflict means another runner saved the saga first.
+	ErrConflict = errors.New("saga was updated concurrently")
+)
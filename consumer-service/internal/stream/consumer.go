@@ -0,0 +1,175 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+
+	"consumer-service/internal/metrics"
+)
+
+// ErrPermanent marks a failure that no redelivery can fix, such as a payload
+// that does not decode. Handlers wrap it so the message is dead-lettered
+// instead of retried.
+var ErrPermanent = errors.New("permanent failure")
+
+// Handler processes one message. It must be safe to call again with the same
+// message, since JetStream redelivers anything that is not acked.
+type Handler func(ctx context.Context, data []byte) error
+
+type ConsumerConfig struct {
+	Stream        string
+	Durable       string
+	FilterSubject string
+	AckWait       time.Duration
+	MaxDeliver    int
+	Backoff       []time.Duration
+	// DeadLetterSubject receives messages that failed permanently or ran out
+	// of attempts. When empty they are only terminated.
+	DeadLetterSubject string
+
+	// Workers is the number of messages handled concurrently.
+	Workers int
+	// QueueSize is how many messages may wait for each worker.
+	QueueSize int
+	// MaxInFlight caps the messages delivered but not yet acked.
+	MaxInFlight int
+	// Key returns the ordering key of a message. Messages with the same key
+	// are handled one at a time, in the order they were delivered.
+	Key func(data []byte) string
+}
+
+// Consumer feeds the messages of a durable pull consumer to a Handler on a
+// pool of workers and acks, naks or dead-letters each one depending on the
+// outcome.
+type Consumer struct {
+	js       jetstream.JetStream
+	consumer jetstream.Consumer
+	handler  Handler
+	cfg      ConsumerConfig
+
+	pool       *pool
+	consumeCtx jetstream.ConsumeContext
+}
+
+// NewConsumer creates or updates the durable consumer described by cfg.
+// Delivery attempts are counted here rather than capped by the server, so a
+// message is only given up on once it is safely in the dead-letter subject.
+func NewConsumer(ctx context.Context, js jetstream.JetStream, cfg ConsumerConfig, handler Handler) (*Consumer, error) {
+	consumer, err := js.CreateOrUpdateConsumer(ctx, cfg.Stream, jetstream.ConsumerConfig{
+		Durable:       cfg.Durable,
+		FilterSubject: cfg.FilterSubject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       cfg.AckWait,
+		MaxDeliver:    -1,
+		MaxAckPending: cfg.MaxInFlight,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Consumer{js: js, consumer: consumer, handler: handler, cfg: cfg}, nil
+}
+
+// Start begins pulling messages and handing them to the workers.
+func (c *Consumer) Start() error {
+	c.pool = newPool(c.cfg.Workers, c.cfg.QueueSize, c.handle)
+
+	var opts []jetstream.PullConsumeOpt
+	if c.cfg.MaxInFlight > 0 {
+		opts = append(opts, jetstream.PullMaxMessages(c.cfg.MaxInFlight))
+	}
+	consumeCtx, err := c.consumer.Consume(c.dispatch, opts...)
+	if err != nil {
+		return err
+	}
+	c.consumeCtx = consumeCtx
+	return nil
+}
+
+// Stop stops pulling messages and waits for the workers to finish the ones
+// they already hold, or for ctx to end. Messages that were pulled but not
+// handled are never acked, so JetStream redelivers them.
+func (c *Consumer) Stop(ctx context.Context) error {
+	if c.consumeCtx != nil {
+		c.consumeCtx.Stop()
+	}
+	if c.pool == nil {
+		return nil
+	}
+	return c.pool.close(ctx)
+}
+
+func (c *Consumer) dispatch(msg jetstream.Msg) {
+	var key string
+	if c.cfg.Key != nil {
+		key = c.cfg.Key(msg.Data())
+	}
+	if !c.pool.submit(key, msg) {
+		// Shutting down; let another consumer have it right away
+		if err := msg.Nak(); err != nil {
+			log.Printf("Failed to nak %s message: %v", msg.Subject(), err)
+		}
+	}
+}
+
+func (c *Consumer) handle(msg jetstream.Msg) {
+	// The message may have waited in the queue; restart its ack timer
+	if err := msg.InProgress(); err != nil {
+		log.Printf("Failed to extend ack wait of %s message: %v", msg.Subject(), err)
+	}
+
+	attempt := uint64(1)
+	if meta, err := msg.Metadata(); err == nil {
+		attempt = meta.NumDelivered
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.AckWait)
+	defer cancel()
+
+	err := c.handler(ctx, msg.Data())
+	switch {
+	case err == nil:
+		if err := msg.Ack(); err != nil {
+			log.Printf("Failed to ack %s message: %v", msg.Subject(), err)
+		}
+
+	case errors.Is(err, ErrPermanent), c.cfg.MaxDeliver > 0 && attempt >= uint64(c.cfg.MaxDeliver):
+		log.Printf("Giving up on %s message after %d attempts: %v", msg.Subject(), attempt, err)
+		if dlqErr := c.deadLetter(msg, attempt, err); dlqErr != nil {
+			// Keep the message until it can be dead-lettered
+			log.Printf("Failed to dead-letter %s message: %v", msg.Subject(), dlqErr)
+			c.nak(msg, attempt)
+			return
+		}
+		metrics.EventsDeadLettered.Add(1)
+		if err := msg.TermWithReason(err.Error()); err != nil {
+			log.Printf("Failed to terminate %s message: %v", msg.Subject(), err)
+		}
+
+	default:
+		log.Printf("Attempt %d for %s message failed: %v", attempt, msg.Subject(), err)
+		metrics.EventsRetried.Add(1)
+		c.nak(msg, attempt)
+	}
+}
+
+func (c *Consumer) nak(msg jetstream.Msg, attempt uint64) {
+	if err := msg.NakWithDelay(c.backoff(attempt)); err != nil {
+		log.Printf("Failed to nak %s message: %v", msg.Subject(), err)
+	}
+}
+
+func (c *Consumer) backoff(attempt uint64) time.Duration {
+	if len(c.cfg.Backoff) == 0 {
+		return 0
+	}
+	i := int(attempt) - 1
+	if i >= len(c.cfg.Backoff) {
+		i = len(c.cfg.Backoff) - 1
+	}
+	return c.cfg.Backoff[i]
+}
@@ -0,0 +1,275 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"events"
+)
+
+const (
+	testStream     = "ORDERS"
+	testSubject    = "order.created"
+	testDeadLetter = "order.created.dlq"
+)
+
+var errTransient = errors.New("inventory unavailable")
+
+// runJetStream starts an in-process nats-server with JetStream enabled and
+// returns a JetStream context connected to it with the order stream created.
+func runJetStream(t *testing.T) jetstream.JetStream {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats-server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server did not start")
+	}
+	t.Cleanup(func() {
+		srv.Shutdown()
+		srv.WaitForShutdown()
+	})
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to nats-server: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatalf("failed to create JetStream context: %v", err)
+	}
+	if err := events.EnsureStream(context.Background(), js, testStream, []string{"order.>"}); err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+	return js
+}
+
+// recorder is a Handler that fails with the errors it is given, one per
+// call, and succeeds once they run out. It records when it was called.
+type recorder struct {
+	mu    sync.Mutex
+	errs  []error
+	calls []time.Time
+}
+
+func (r *recorder) handle(ctx context.Context, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, time.Now())
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	if len(r.errs) > 1 {
+		r.errs = r.errs[1:]
+	}
+	return err
+}
+
+func (r *recorder) callTimes() []time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Time(nil), r.calls...)
+}
+
+func startConsumer(t *testing.T, js jetstream.JetStream, deadLetter string, handler Handler) jetstream.Consumer {
+	t.Helper()
+
+	ctx := context.Background()
+	c, err := NewConsumer(ctx, js, ConsumerConfig{
+		Stream:            testStream,
+		Durable:           "test",
+		FilterSubject:     testSubject,
+		AckWait:           5 * time.Second,
+		MaxDeliver:        3,
+		Backoff:           []time.Duration{100 * time.Millisecond, 300 * time.Millisecond},
+		DeadLetterSubject: deadLetter,
+		Workers:           2,
+		QueueSize:         4,
+		MaxInFlight:       16,
+	}, handler)
+	if err != nil {
+		t.Fatalf("failed to create consumer: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("failed to start consumer: %v", err)
+	}
+	t.Cleanup(func() {
+		stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := c.Stop(stopCtx); err != nil {
+			t.Errorf("failed to stop consumer: %v", err)
+		}
+	})
+
+	consumer, err := js.Consumer(ctx, testStream, "test")
+	if err != nil {
+		t.Fatalf("failed to look up consumer: %v", err)
+	}
+	return consumer
+}
+
+func publish(t *testing.T, js jetstream.JetStream, data string) {
+	t.Helper()
+	if _, err := js.Publish(context.Background(), testSubject, []byte(data)); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+}
+
+// waitSettled waits until every delivered message of consumer is acked or
+// terminated.
+func waitSettled(t *testing.T, consumer jetstream.Consumer, delivered uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, err := consumer.Info(context.Background())
+		if err != nil {
+			t.Fatalf("failed to get consumer info: %v", err)
+		}
+		if info.AckFloor.Consumer >= delivered && info.NumAckPending == 0 && info.NumPending == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("messages not settled: delivered %d, ack floor %d, ack pending %d",
+				info.Delivered.Consumer, info.AckFloor.Consumer, info.NumAckPending)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func deadLetters(t *testing.T, js jetstream.JetStream) []DeadLetter {
+	t.Helper()
+	letters, err := ListDeadLetters(context.Background(), js, testStream, testDeadLetter, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to list dead letters: %v", err)
+	}
+	return letters
+}
+
+func TestConsumerAcksHandledMessage(t *testing.T) {
+	js := runJetStream(t)
+	rec := &recorder{}
+	consumer := startConsumer(t, js, testDeadLetter, rec.handle)
+
+	publish(t, js, "order-1")
+	waitSettled(t, consumer, 1)
+
+	if calls := len(rec.callTimes()); calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if letters := deadLetters(t, js); len(letters) != 0 {
+		t.Fatalf("got %d dead letters, want none", len(letters))
+	}
+}
+
+func TestConsumerRetriesWithBackoff(t *testing.T) {
+	js := runJetStream(t)
+	rec := &recorder{errs: []error{errTransient, errTransient, nil}}
+	consumer := startConsumer(t, js, testDeadLetter, rec.handle)
+
+	publish(t, js, "order-1")
+	// The third delivery succeeds; attempts are counted in the consumer's
+	// delivered sequence
+	waitSettled(t, consumer, 3)
+
+	calls := rec.callTimes()
+	if len(calls) != 3 {
+		t.Fatalf("handler called %d times, want 3", len(calls))
+	}
+	for i, want := range []time.Duration{100 * time.Millisecond, 300 * time.Millisecond} {
+		if got := calls[i+1].Sub(calls[i]); got < want {
+			t.Errorf("retry %d came after %v, want at least %v", i+1, got, want)
+		}
+	}
+	if letters := deadLetters(t, js); len(letters) != 0 {
+		t.Fatalf("got %d dead letters, want none", len(letters))
+	}
+}
+
+func TestConsumerDeadLettersPermanentFailure(t *testing.T) {
+	js := runJetStream(t)
+	rec := &recorder{errs: []error{fmt.Errorf("%w: payload does not decode", ErrPermanent)}}
+	consumer := startConsumer(t, js, testDeadLetter, rec.handle)
+
+	publish(t, js, "not an order")
+	waitSettled(t, consumer, 1)
+
+	if calls := len(rec.callTimes()); calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	letters := deadLetters(t, js)
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	letter := letters[0]
+	if letter.OriginalSubject != testSubject || letter.Attempts != 1 || string(letter.Data) != "not an order" {
+		t.Errorf("dead letter = %+v, want subject %s, 1 attempt and the original data", letter, testSubject)
+	}
+	if letter.Error != "permanent failure: payload does not decode" {
+		t.Errorf("dead letter error = %q", letter.Error)
+	}
+}
+
+func TestConsumerTerminatesWithoutDeadLetterSubject(t *testing.T) {
+	js := runJetStream(t)
+	rec := &recorder{errs: []error{ErrPermanent}}
+	consumer := startConsumer(t, js, "", rec.handle)
+
+	publish(t, js, "not an order")
+	waitSettled(t, consumer, 1)
+
+	// A terminated message is not redelivered
+	time.Sleep(300 * time.Millisecond)
+	if calls := len(rec.callTimes()); calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if letters := deadLetters(t, js); len(letters) != 0 {
+		t.Fatalf("got %d dead letters, want none", len(letters))
+	}
+}
+
+func TestConsumerGivesUpAfterMaxDeliver(t *testing.T) {
+	js := runJetStream(t)
+	rec := &recorder{errs: []error{errTransient}}
+	consumer := startConsumer(t, js, testDeadLetter, rec.handle)
+
+	publish(t, js, "order-1")
+	waitSettled(t, consumer, 3)
+
+	// MaxDeliver is 3: the third failure dead-letters the message and it is
+	// not delivered again
+	time.Sleep(500 * time.Millisecond)
+	if calls := len(rec.callTimes()); calls != 3 {
+		t.Fatalf("handler called %d times, want 3", calls)
+	}
+	letters := deadLetters(t, js)
+	if len(letters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(letters))
+	}
+	if letters[0].Attempts != 3 || letters[0].Error != errTransient.Error() {
+		t.Errorf("dead letter has %d attempts and error %q, want 3 and %q",
+			letters[0].Attempts, letters[0].Error, errTransient.Error())
+	}
+}
@@ -0,0 +1,182 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Headers added to a dead-lettered message next to the original ones.
+const (
+	HeaderDLQError    = "Dlq-Error"
+	HeaderDLQAttempts = "Dlq-Attempts"
+	HeaderDLQSubject  = "Dlq-Original-Subject"
+	HeaderDLQSequence = "Dlq-Original-Sequence"
+	HeaderDLQFailedAt = "Dlq-Failed-At"
+)
+
+// DeadLetter is a message stored on a dead-letter subject.
+type DeadLetter struct {
+	Sequence        uint64
+	Time            time.Time
+	OriginalSubject string
+	Error           string
+	Attempts        int
+	Header          nats.Header
+	Data            []byte
+}
+
+// deadLetter copies msg, its headers and the reason it failed to the
+// configured dead-letter subject.
+func (c *Consumer) deadLetter(msg jetstream.Msg, attempt uint64, cause error) error {
+	if c.cfg.DeadLetterSubject == "" {
+		return nil
+	}
+
+	dlq := nats.NewMsg(c.cfg.DeadLetterSubject)
+	for key, values := range msg.Headers() {
+		// The original ID would make the stream drop the copy as a duplicate
+		if key == nats.MsgIdHdr {
+			continue
+		}
+		for _, value := range values {
+			dlq.Header.Add(key, value)
+		}
+	}
+	dlq.Header.Set(HeaderDLQError, cause.Error())
+	dlq.Header.Set(HeaderDLQAttempts, strconv.FormatUint(attempt, 10))
+	dlq.Header.Set(HeaderDLQSubject, msg.Subject())
+	dlq.Header.Set(HeaderDLQFailedAt, time.Now().UTC().Format(time.RFC3339))
+	if meta, err := msg.Metadata(); err == nil {
+		dlq.Header.Set(HeaderDLQSequence, strconv.FormatUint(meta.Sequence.Stream, 10))
+	}
+	dlq.Data = msg.Data()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := c.js.PublishMsg(ctx, dlq)
+	return err
+}
+
+// ListDeadLetters returns the messages stored on subject in the stream,
+// oldest first. A non-zero since or until limits them to that time range.
+func ListDeadLetters(ctx context.Context, js jetstream.JetStream, streamName, subject string, since, until time.Time) ([]DeadLetter, error) {
+	s, err := js.Stream(ctx, streamName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.Info(ctx, jetstream.WithSubjectFilter(subject))
+	if err != nil {
+		return nil, err
+	}
+	if info.State.Subjects[subject] == 0 {
+		return nil, nil
+	}
+
+	cfg := jetstream.OrderedConsumerConfig{FilterSubjects: []string{subject}}
+	if !since.IsZero() {
+		cfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+		cfg.OptStartTime = &since
+	}
+	consumer, err := s.OrderedConsumer(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var letters []DeadLetter
+	for {
+		msg, err := consumer.Next(jetstream.FetchMaxWait(2 * time.Second))
+		if err != nil {
+			// Nothing newer than since
+			if errors.Is(err, nats.ErrTimeout) {
+				return letters, nil
+			}
+			return nil, err
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			return nil, err
+		}
+		if !until.IsZero() && meta.Timestamp.After(until) {
+			return letters, nil
+		}
+
+		letter := DeadLetter{
+			Sequence:        meta.Sequence.Stream,
+			Time:            meta.Timestamp,
+			OriginalSubject: msg.Headers().Get(HeaderDLQSubject),
+			Error:           msg.Headers().Get(HeaderDLQError),
+			Header:          msg.Headers(),
+			Data:            msg.Data(),
+		}
+		letter.Attempts, _ = strconv.Atoi(msg.Headers().Get(HeaderDLQAttempts))
+		letters = append(letters, letter)
+
+		if meta.NumPending == 0 {
+			return letters, nil
+		}
+	}
+}
+
+// GetDeadLetter returns the dead-lettered message stored at seq.
+func GetDeadLetter(ctx context.Context, js jetstream.JetStream, streamName, subject string, seq uint64) (*DeadLetter, error) {
+	s, err := js.Stream(ctx, streamName)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := s.GetMsg(ctx, seq)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Subject != subject {
+		return nil, jetstream.ErrMsgNotFound
+	}
+
+	letter := &DeadLetter{
+		Sequence:        msg.Sequence,
+		Time:            msg.Time,
+		OriginalSubject: msg.Header.Get(HeaderDLQSubject),
+		Error:           msg.Header.Get(HeaderDLQError),
+		Header:          msg.Header,
+		Data:            msg.Data,
+	}
+	letter.Attempts, _ = strconv.Atoi(msg.Header.Get(HeaderDLQAttempts))
+	return letter, nil
+}
+
+// Republish publishes a dead-lettered message back to its original subject
+// without the dead-letter headers and removes it from the dead-letter subject.
+func Republish(ctx context.Context, js jetstream.JetStream, streamName string, letter *DeadLetter) error {
+	if letter.OriginalSubject == "" {
+		return errors.New("dead letter has no original subject")
+	}
+
+	msg := nats.NewMsg(letter.OriginalSubject)
+	for key, values := range letter.Header {
+		if strings.HasPrefix(key, "Dlq-") {
+			continue
+		}
+		for _, value := range values {
+			msg.Header.Add(key, value)
+		}
+	}
+	msg.Data = letter.Data
+	if _, err := js.PublishMsg(ctx, msg); err != nil {
+		return err
+	}
+	return Purge(ctx, js, streamName, letter.Sequence)
+}
+
+// Purge deletes the dead-lettered message stored at seq.
+func Purge(ctx context.Context, js jetstream.JetStream, streamName string, seq uint64) error {
+	s, err := js.Stream(ctx, streamName)
+	if err != nil {
+		return err
+	}
+	return s.DeleteMsg(ctx, seq)
+}
@@ -0,0 +1,110 @@
+package stream
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// pool runs messages on a fixed set of workers. Messages with the same key
+// always go to the same worker, which handles them one at a time in arrival
+// order. Each worker has a bounded queue; submitting to a full queue blocks,
+// which stops the consumer from pulling more messages until it drains.
+type pool struct {
+	mu     sync.RWMutex
+	closed bool
+	// closing is closed by close to release submits blocked on a full queue.
+	closing chan struct{}
+	// sending counts the submits that may still send to a queue, so the
+	// queues are only closed once none can.
+	sending sync.WaitGroup
+	// done is closed once the workers have finished.
+	done   chan struct{}
+	queues []chan jetstream.Msg
+	next   atomic.Uint32
+	wg     sync.WaitGroup
+}
+
+func newPool(workers, queueSize int, handle func(jetstream.Msg)) *pool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &pool{
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		queues:  make([]chan jetstream.Msg, workers),
+	}
+	for i := range p.queues {
+		queue := make(chan jetstream.Msg, queueSize)
+		p.queues[i] = queue
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for msg := range queue {
+				handle(msg)
+			}
+		}()
+	}
+	return p
+}
+
+// submit queues msg on the worker for key. Messages without a key are spread
+// round-robin. It reports false once the pool is closed, in which case the
+// message was not taken. A submit blocked on a full queue gives up as soon as
+// the pool is closed.
+func (p *pool) submit(key string, msg jetstream.Msg) bool {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return false
+	}
+	p.sending.Add(1)
+	p.mu.RUnlock()
+	defer p.sending.Done()
+
+	var i uint32
+	if key == "" {
+		i = p.next.Add(1)
+	} else {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		i = h.Sum32()
+	}
+	select {
+	case p.queues[i%uint32(len(p.queues))] <- msg:
+		return true
+	case <-p.closing:
+		return false
+	}
+}
+
+// close stops accepting messages and waits until the workers have handled
+// everything already queued, or until ctx is done.
+func (p *pool) close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+		go func() {
+			// Blocked submits return once closing is closed; after that
+			// nothing sends to the queues any more
+			p.sending.Wait()
+			for _, queue := range p.queues {
+				close(queue)
+			}
+			p.wg.Wait()
+			close(p.done)
+		}()
+	}
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,68 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestPoolCloseReturnsWhileHandlerHangs(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 1)
+	p := newPool(1, 1, func(jetstream.Msg) {
+		started <- struct{}{}
+		<-release
+	})
+
+	// The first message hangs the only worker and the second fills its
+	// queue, so the third submit blocks
+	p.submit("", nil)
+	<-started
+	p.submit("", nil)
+	submitted := make(chan bool)
+	go func() { submitted <- p.submit("", nil) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := p.close(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("close returned %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("close took %v, want it bounded by its context", elapsed)
+	}
+
+	select {
+	case ok := <-submitted:
+		if ok {
+			t.Fatal("blocked submit reported the message as taken after close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked submit did not return after close")
+	}
+	if p.submit("", nil) {
+		t.Fatal("submit after close reported the message as taken")
+	}
+}
+
+func TestPoolCloseDrainsQueuedMessages(t *testing.T) {
+	handled := make(chan struct{}, 4)
+	p := newPool(2, 2, func(jetstream.Msg) { handled <- struct{}{} })
+
+	for i := 0; i < 4; i++ {
+		if !p.submit("", nil) {
+			t.Fatalf("submit %d was refused", i)
+		}
+	}
+	if err := p.close(context.Background()); err != nil {
+		t.Fatalf("close returned %v", err)
+	}
+	if len(handled) != 4 {
+		t.Fatalf("handled %d messages before close returned, want 4", len(handled))
+	}
+}
@@ -0,0 +1,137 @@
+// Package events defines the envelope every domain event is published in and
+// the schemas of the event payloads.
+//
+// Envelopes follow the CloudEvents 1.0 JSON format. CloudEvents attribute
+// names may only contain lowercase letters and digits, so the schema version
+// and correlation ID travel as the schemaversion and correlationid extension
+// attributes.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	TypeOrderCreated       = "order.created"
+	TypeOrderStatusChanged = "order.status_changed"
+	TypeOrderCancelled     = "order.cancelled"
+
+	SpecVersion = "1.0"
+	// ContentType is the media type of an encoded envelope.
+	ContentType = "application/cloudevents+json"
+)
+
+// Envelope carries an event payload together with its metadata.
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	SchemaVersion   string          `json:"schemaversion"`
+	CorrelationID   string          `json:"correlationid,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Meta is the envelope metadata supplied by the publisher.
+type Meta struct {
+	ID     string
+	Type   string
+	Source string
+	// Subject is the ID of the entity the event is about.
+	Subject       string
+	Time          time.Time
+	CorrelationID string
+}
+
+var (
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// Encode wraps payload in an envelope stamped with the current schema
+// version of meta.Type.
+func Encode(meta Meta, payload proto.Message) ([]byte, error) {
+	s, ok := schemas[meta.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, meta.Type)
+	}
+	if name := string(proto.MessageName(payload)); name != s.message {
+		return nil, fmt.Errorf("%s payload must be %s, not %s", meta.Type, s.message, name)
+	}
+
+	data, err := marshalOptions.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Time.IsZero() {
+		meta.Time = time.Now()
+	}
+
+	return json.Marshal(Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              meta.ID,
+		Type:            meta.Type,
+		Source:          meta.Source,
+		Subject:         meta.Subject,
+		Time:            meta.Time.UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   s.version.String(),
+		CorrelationID:   meta.CorrelationID,
+		Data:            data,
+	})
+}
+
+// Parse decodes the envelope without looking at its payload.
+func Parse(data []byte) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	if env.SpecVersion != SpecVersion {
+		return nil, fmt.Errorf("%w: specversion %q", ErrInvalidEnvelope, env.SpecVersion)
+	}
+	if env.ID == "" || env.Type == "" {
+		return nil, fmt.Errorf("%w: missing id or type", ErrInvalidEnvelope)
+	}
+	return &env, nil
+}
+
+// Decode parses an envelope and decodes its payload into payload, which must
+// be the message registered for the envelope's type. Payloads written with
+// an older minor version are upcast first; those of an unknown major version
+// are rejected with ErrUnsupportedVersion.
+func Decode(data []byte, payload proto.Message) (*Envelope, error) {
+	env, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := schemas[env.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownType, env.Type)
+	}
+	if name := string(proto.MessageName(payload)); name != s.message {
+		return nil, fmt.Errorf("%s payload is %s, not %s", env.Type, s.message, name)
+	}
+
+	version, err := ParseVersion(env.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	payloadData, err := s.upcast(version, env.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalOptions.Unmarshal(payloadData, payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	return env, nil
+}
@@ -0,0 +1,170 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	eventspb "events/proto"
+)
+
+// envelopeJSON builds an encoded envelope by hand, as an older or newer
+// publisher might have written it.
+func envelopeJSON(t *testing.T, eventType, schemaVersion, data string) []byte {
+	t.Helper()
+	out, err := json.Marshal(Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              "evt-1",
+		Type:            eventType,
+		Source:          "test",
+		Time:            time.Unix(1700000000, 0).UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   schemaVersion,
+		Data:            json.RawMessage(data),
+	})
+	if err != nil {
+		t.Fatalf("failed to build envelope: %v", err)
+	}
+	return out
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	payload := &eventspb.OrderCreated{
+		OrderId: "order-1",
+		UserId:  "user-1",
+		Items: []*eventspb.OrderItem{
+			{ProductId: "p1", Quantity: 2, Price: 4.5, Name: "Mug", Category: "Kitchen", Subtotal: 9},
+		},
+		Subtotal:  9,
+		Total:     9,
+		Status:    "pending",
+		CreatedAt: 1700000000,
+	}
+	meta := Meta{
+		ID:            "order-1:created",
+		Type:          TypeOrderCreated,
+		Source:        "order-service",
+		Subject:       "order-1",
+		Time:          time.Unix(1700000000, 0).In(time.FixedZone("CET", 3600)),
+		CorrelationID: "order-1",
+	}
+
+	data, err := Encode(meta, payload)
+	if err != nil {
+		t.Fatalf("Encode returned %v", err)
+	}
+	var decoded eventspb.OrderCreated
+	env, err := Decode(data, &decoded)
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+
+	if !proto.Equal(&decoded, payload) {
+		t.Errorf("payload = %v, want %v", &decoded, payload)
+	}
+	want := Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              meta.ID,
+		Type:            meta.Type,
+		Source:          meta.Source,
+		Subject:         meta.Subject,
+		Time:            meta.Time.UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   "1.1",
+		CorrelationID:   meta.CorrelationID,
+	}
+	env.Data = nil
+	if !env.Time.Equal(want.Time) || env.Time.Location() != time.UTC {
+		t.Errorf("time = %v, want %v in UTC", env.Time, want.Time)
+	}
+	env.Time = want.Time
+	if !reflect.DeepEqual(*env, want) {
+		t.Errorf("envelope = %+v, want %+v", *env, want)
+	}
+}
+
+func TestEncodeDefaultsTimeToNow(t *testing.T) {
+	before := time.Now().Add(-time.Second)
+	data, err := Encode(Meta{ID: "evt-1", Type: TypeOrderStatusChanged}, &eventspb.OrderStatusChanged{OrderId: "order-1"})
+	if err != nil {
+		t.Fatalf("Encode returned %v", err)
+	}
+	env, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse returned %v", err)
+	}
+	if env.Time.Before(before) || env.Time.After(time.Now()) {
+		t.Errorf("time = %v, want about now", env.Time)
+	}
+}
+
+func TestEncodeRejectsMismatchedPayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventType string
+		payload   proto.Message
+		wantErr   error
+	}{
+		{"unknown type", "order.shipped", &eventspb.OrderCreated{}, ErrUnknownType},
+		{"wrong payload", TypeOrderCreated, &eventspb.OrderCancelled{}, nil},
+	}
+	for _, tt := range tests {
+		_, err := Encode(Meta{ID: "evt-1", Type: tt.eventType}, tt.payload)
+		if err == nil {
+			t.Errorf("%s: Encode succeeded, want an error", tt.name)
+			continue
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Encode returned %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDecodeRejectsInvalidEnvelopes(t *testing.T) {
+	valid := `{"order_id":"order-1"}`
+	tests := []struct {
+		name    string
+		data    []byte
+		payload proto.Message
+		wantErr error
+	}{
+		{"not json", []byte("order-1"), &eventspb.OrderCreated{}, ErrInvalidEnvelope},
+		{"other specversion", []byte(`{"specversion":"0.3","id":"evt-1","type":"order.created"}`), &eventspb.OrderCreated{}, ErrInvalidEnvelope},
+		{"no id", []byte(`{"specversion":"1.0","type":"order.created"}`), &eventspb.OrderCreated{}, ErrInvalidEnvelope},
+		{"unknown type", envelopeJSON(t, "order.shipped", "1.0", valid), &eventspb.OrderCreated{}, ErrUnknownType},
+		{"unknown major version", envelopeJSON(t, TypeOrderCreated, "2.0", valid), &eventspb.OrderCreated{}, ErrUnsupportedVersion},
+		{"older major version", envelopeJSON(t, TypeOrderCancelled, "0.9", valid), &eventspb.OrderCancelled{}, ErrUnsupportedVersion},
+		{"malformed version", envelopeJSON(t, TypeOrderCreated, "1", valid), &eventspb.OrderCreated{}, ErrUnsupportedVersion},
+		{"missing version", envelopeJSON(t, TypeOrderCreated, "", valid), &eventspb.OrderCreated{}, ErrUnsupportedVersion},
+		{"malformed payload", envelopeJSON(t, TypeOrderStatusChanged, "1.0", `{"order_id":1}`), &eventspb.OrderStatusChanged{}, ErrInvalidEnvelope},
+		{"wrong payload", envelopeJSON(t, TypeOrderCreated, "1.1", valid), &eventspb.OrderCancelled{}, nil},
+	}
+	for _, tt := range tests {
+		_, err := Decode(tt.data, tt.payload)
+		if err == nil {
+			t.Errorf("%s: Decode succeeded, want an error", tt.name)
+			continue
+		}
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Decode returned %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDecodeAcceptsNewerMinorVersion(t *testing.T) {
+	// A newer publisher may add fields this reader does not know yet
+	data := envelopeJSON(t, TypeOrderStatusChanged, "1.3", `{"order_id":"order-1","to_status":"paid","carrier":"dhl"}`)
+
+	var payload eventspb.OrderStatusChanged
+	env, err := Decode(data, &payload)
+	if err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if env.SchemaVersion != "1.3" || payload.OrderId != "order-1" || payload.ToStatus != "paid" {
+		t.Errorf("decoded %+v with payload %v", env, &payload)
+	}
+}
@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// EnsureStream creates the JetStream stream that stores order events if it
+// does not exist yet. An existing stream is left as it is, so operators can
+// tune retention without it being reset on every restart. Publishers and
+// consumers both call it, so whichever starts first creates the stream.
+func EnsureStream(ctx context.Context, js jetstream.JetStream, name string, subjects []string) error {
+	_, err := js.Stream(ctx, name)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return err
+	}
+
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:       name,
+		Subjects:   subjects,
+		Storage:    jetstream.FileStorage,
+		Duplicates: 2 * time.Minute,
+	})
+	if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+		return nil
+	}
+	return err
+}
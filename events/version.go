@@ -0,0 +1,136 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownType        = errors.New("unknown event type")
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrInvalidEnvelope    = errors.New("invalid event envelope")
+)
+
+// Version is a payload schema version. Minor versions only add fields, so a
+// reader understands every minor version of its own major; a new major
+// version means the payload changed incompatibly.
+type Version struct {
+	Major int
+	Minor int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+func ParseVersion(s string) (Version, error) {
+	major, minor, ok := strings.Cut(s, ".")
+	if !ok {
+		return Version{}, fmt.Errorf("%w: %q", ErrUnsupportedVersion, s)
+	}
+	var v Version
+	var err error
+	if v.Major, err = strconv.Atoi(major); err != nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrUnsupportedVersion, s)
+	}
+	if v.Minor, err = strconv.Atoi(minor); err != nil {
+		return Version{}, fmt.Errorf("%w: %q", ErrUnsupportedVersion, s)
+	}
+	return v, nil
+}
+
+// Upcaster rewrites a payload written with one minor version into the shape
+// of the next one, e.g. filling in a field that minor version introduced.
+type Upcaster func(data map[string]interface{}) error
+
+type schema struct {
+	version Version
+	message string
+	// upcasters[n] turns a minor version n payload into minor version n+1.
+	upcasters map[int]Upcaster
+}
+
+// schemas lists every event type with its current schema version and the
+// protobuf message its payload decodes into.
+var schemas = map[string]*schema{
+	TypeOrderCreated: {
+		version: Version{Major: 1, Minor: 1},
+		message: "events.OrderCreated",
+		upcasters: map[int]Upcaster{
+			0: addOrderSubtotals,
+		},
+	},
+	TypeOrderStatusChanged: {
+		version: Version{Major: 1, Minor: 0},
+		message: "events.OrderStatusChanged",
+	},
+	TypeOrderCancelled: {
+		version: Version{Major: 1, Minor: 0},
+		message: "events.OrderCancelled",
+	},
+}
+
+// CurrentVersion returns the schema version new events of eventType are
+// written with.
+func CurrentVersion(eventType string) (Version, error) {
+	s, ok := schemas[eventType]
+	if !ok {
+		return Version{}, fmt.Errorf("%w: %q", ErrUnknownType, eventType)
+	}
+	return s.version, nil
+}
+
+// upcast brings a payload of the given version up to the current minor
+// version of its schema. Payloads of another major version are rejected;
+// payloads of a newer minor version are kept as they are, since the fields
+// they add are simply ignored.
+func (s *schema) upcast(version Version, data json.RawMessage) (json.RawMessage, error) {
+	if version.Major != s.version.Major {
+		return nil, fmt.Errorf("%w: %s, want %d.x", ErrUnsupportedVersion, version, s.version.Major)
+	}
+	if version.Minor >= s.version.Minor {
+		return data, nil
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	for minor := version.Minor; minor < s.version.Minor; minor++ {
+		if up, ok := s.upcasters[minor]; ok {
+			if err := up(payload); err != nil {
+				return nil, fmt.Errorf("upcast from %d.%d: %w", version.Major, minor, err)
+			}
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// addOrderSubtotals upcasts order.created 1.0, which had no subtotals, by
+// deriving them from the item prices and quantities, rounded to cents as
+// order-service rounds them.
+func addOrderSubtotals(data map[string]interface{}) error {
+	items, _ := data["items"].([]interface{})
+	var subtotal float64
+	for _, raw := range items {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return errors.New("order item is not an object")
+		}
+		price, _ := item["price"].(float64)
+		quantity, _ := item["quantity"].(float64)
+		itemSubtotal := roundCents(price * quantity)
+		item["subtotal"] = itemSubtotal
+		subtotal += itemSubtotal
+	}
+	data["subtotal"] = roundCents(subtotal)
+	return nil
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	eventspb "events/proto"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.0", Version{Major: 1, Minor: 0}},
+		{"1.1", Version{Major: 1, Minor: 1}},
+		{"12.34", Version{Major: 12, Minor: 34}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || got.String() != tt.in {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1", "v1.0", "1.x", "1.0.0"} {
+		if _, err := ParseVersion(in); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("ParseVersion(%q) returned %v, want %v", in, err, ErrUnsupportedVersion)
+		}
+	}
+}
+
+func TestCurrentVersion(t *testing.T) {
+	if v, err := CurrentVersion(TypeOrderCreated); err != nil || v != (Version{Major: 1, Minor: 1}) {
+		t.Errorf("CurrentVersion(%q) = %v, %v, want 1.1", TypeOrderCreated, v, err)
+	}
+	if _, err := CurrentVersion("order.shipped"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("CurrentVersion of an unknown type returned %v, want %v", err, ErrUnknownType)
+	}
+}
+
+func TestDecodeUpcastsOrderCreated(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		data    string
+		want    *eventspb.OrderCreated
+	}{
+		{
+			name:    "1.0 gets subtotals",
+			version: "1.0",
+			data: `{"order_id":"order-1","total":64.47,"items":[
+				{"product_id":"p1","quantity":3,"price":19.99},
+				{"product_id":"p2","quantity":1,"price":4.5}]}`,
+			want: &eventspb.OrderCreated{
+				OrderId: "order-1",
+				Total:   64.47,
+				Items: []*eventspb.OrderItem{
+					{ProductId: "p1", Quantity: 3, Price: 19.99, Subtotal: 59.97},
+					{ProductId: "p2", Quantity: 1, Price: 4.5, Subtotal: 4.5},
+				},
+				Subtotal: 64.47,
+			},
+		},
+		{
+			name:    "1.0 subtotals are rounded to cents",
+			version: "1.0",
+			data:    `{"order_id":"order-1","items":[{"product_id":"p1","quantity":3,"price":0.1}]}`,
+			want: &eventspb.OrderCreated{
+				OrderId:  "order-1",
+				Items:    []*eventspb.OrderItem{{ProductId: "p1", Quantity: 3, Price: 0.1, Subtotal: 0.3}},
+				Subtotal: 0.3,
+			},
+		},
+		{
+			name:    "1.0 without items",
+			version: "1.0",
+			data:    `{"order_id":"order-1"}`,
+			want:    &eventspb.OrderCreated{OrderId: "order-1"},
+		},
+		{
+			// The publisher's own figures are kept, not recomputed
+			name:    "1.1 is left alone",
+			version: "1.1",
+			data: `{"order_id":"order-1","subtotal":10,"items":[
+				{"product_id":"p1","quantity":3,"price":2,"subtotal":5}]}`,
+			want: &eventspb.OrderCreated{
+				OrderId:  "order-1",
+				Items:    []*eventspb.OrderItem{{ProductId: "p1", Quantity: 3, Price: 2, Subtotal: 5}},
+				Subtotal: 10,
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got eventspb.OrderCreated
+		if _, err := Decode(envelopeJSON(t, TypeOrderCreated, tt.version, tt.data), &got); err != nil {
+			t.Errorf("%s: Decode returned %v", tt.name, err)
+			continue
+		}
+		if !proto.Equal(&got, tt.want) {
+			t.Errorf("%s: payload = %v, want %v", tt.name, &got, tt.want)
+		}
+	}
+}
+
+func TestDecodeRejectsMalformedOrderCreated10(t *testing.T) {
+	data := envelopeJSON(t, TypeOrderCreated, "1.0", `{"order_id":"order-1","items":["p1"]}`)
+	if _, err := Decode(data, &eventspb.OrderCreated{}); err == nil {
+		t.Error("Decode succeeded on an item that is not an object")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
 
+	"authz"
+
 	"inventory-service/internal/config"
 	"inventory-service/internal/controller"
 	"inventory-service/internal/repository"
@@ -41,14 +43,47 @@ func main() {
 
 	// Initialize repositories
 	productRepo := repository.NewProductRepository(db)
+	if err := repository.EnsureProductIndexes(db); err != nil {
+		log.Fatalf("Error creating product indexes: %v", err)
+	}
 	cacheRepo := repository.NewProductCacheRepository(redisClient)
+	reservationRepo := repository.NewReservationRepository(db)
+	if err := repository.EnsureReservationIndexes(db); err != nil {
+		log.Fatalf("Error creating reservation indexes: %v", err)
+	}
 
 	// Initialize use case with Redis caching
-	productUseCase := usecase.NewProductUseCase(productRepo, cacheRepo)
+	suggestIndex := repository.NewSuggestIndex()
+	productUseCase := usecase.NewProductUseCase(productRepo, cacheRepo, suggestIndex)
+	reservationUseCase := usecase.NewReservationUseCase(reservationRepo, cacheRepo, suggestIndex, cfg.ReservationTTL)
+
+	// Fill the in-memory suggestion index before serving, popularity first
+	// so the products are ranked in one pass
+	if err := reservationUseCase.LoadUnitsSold(); err != nil {
+		log.Fatalf("Error loading units sold: %v", err)
+	}
+	if err := productUseCase.BuildSuggestIndex(); err != nil {
+		log.Fatalf("Error building suggestion index: %v", err)
+	}
+
+	// Return stock held by reservations that were never committed
+	go reservationUseCase.StartExpiryWorker(ctx, cfg.ReservationSweepInterval)
+
+	// Verify the tokens callers send
+	verifier, err := authz.NewVerifier(authz.VerifierConfig{
+		Algorithm:     cfg.JWTAlgorithm,
+		Secret:        cfg.JWTSecret,
+		PublicKeyPath: cfg.JWTPublicKeyPath,
+		Issuer:        cfg.JWTIssuer,
+		ServiceSecret: cfg.ServiceTokenSecret,
+	})
+	if err != nil {
+		log.Fatalf("Error initializing token verifier: %v", err)
+	}
 
 	// Initialize gRPC server and controller
-	grpcServer := grpc.NewServer()
-	productController := controller.NewProductController(*productUseCase)
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(authz.UnaryServerInterceptor(verifier)))
+	productController := controller.NewProductController(*productUseCase, reservationUseCase)
 	pb.RegisterInventoryServiceServer(grpcServer, productController)
 
 	// Start gRPC server
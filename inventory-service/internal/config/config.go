@@ -3,6 +3,7 @@ package config
 import (
     "context"
     "fmt"
+    "os"
     "time"
 
     "go.mongodb.org/mongo-driver/mongo"
@@ -16,6 +17,18 @@ type Config struct {
     RedisAddr       string // Add this
     RedisPassword   string // Add this
     RedisDB         int    // Add this
+
+    ReservationTTL           time.Duration
+    ReservationSweepInterval time.Duration
+
+    // JWTAlgorithm, JWTSecret, JWTPublicKeyPath and JWTIssuer verify the
+    // access tokens the gateway forwards; ServiceTokenSecret verifies the
+    // service tokens of the other services.
+    JWTAlgorithm       string
+    JWTSecret          string
+    JWTPublicKeyPath   string
+    JWTIssuer          string
+    ServiceTokenSecret string
 }
 
 func NewConfig() *Config {
@@ -26,7 +39,23 @@ func NewConfig() *Config {
         RedisAddr:      "localhost:6379", // Default Redis port
         RedisPassword:   "",
         RedisDB:         0,
+
+        ReservationTTL:           15 * time.Minute,
+        ReservationSweepInterval: 30 * time.Second,
+
+        JWTAlgorithm:       getEnv("JWT_ALGORITHM", "HS256"),
+        JWTSecret:          getEnv("JWT_SECRET", ""),
+        JWTPublicKeyPath:   getEnv("JWT_PUBLIC_KEY_PATH", ""),
+        JWTIssuer:          getEnv("JWT_ISSUER", "user-service"),
+        ServiceTokenSecret: getEnv("SERVICE_TOKEN_SECRET", ""),
+    }
+}
+
+func getEnv(key, defaultValue string) string {
+    if value, exists := os.LookupEnv(key); exists {
+        return value
     }
+    return defaultValue
 }
 
 func ConnectMongoDB(uri string) (*mongo.Database, error) {
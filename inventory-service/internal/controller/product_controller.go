@@ -10,16 +10,19 @@ import (
 	"inventory-service/internal/entity"
 	"inventory-service/internal/usecase"
 	pb "inventory-service/proto"
+	"paging"
 )
 
 type ProductController struct {
 	pb.UnimplementedInventoryServiceServer
-	productUseCase usecase.ProductUseCase
+	productUseCase     usecase.ProductUseCase
+	reservationUseCase *usecase.ReservationUseCase
 }
 
-func NewProductController(productUseCase usecase.ProductUseCase) *ProductController {
+func NewProductController(productUseCase usecase.ProductUseCase, reservationUseCase *usecase.ReservationUseCase) *ProductController {
 	return &ProductController{
-		productUseCase: productUseCase,
+		productUseCase:     productUseCase,
+		reservationUseCase: reservationUseCase,
 	}
 }
 
@@ -75,7 +78,19 @@ func (c *ProductController) UpdateProduct(ctx context.Context, req *pb.ProductRe
 		Category:    req.GetCategory(),
 	}
 
-	if err := c.productUseCase.UpdateProduct(product); err != nil {
+	if mask := req.GetUpdateMask(); mask != nil {
+		updated, err := c.productUseCase.UpdateProductFields(product, mask.GetPaths())
+		if err != nil {
+			if errors.Is(err, entity.ErrInvalidUpdateMask) {
+				return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+			}
+			if errors.Is(err, entity.ErrProductNotFound) {
+				return nil, status.Errorf(codes.NotFound, "product not found")
+			}
+			return nil, status.Errorf(codes.Internal, "failed to update product: %v", err)
+		}
+		product = updated
+	} else if err := c.productUseCase.UpdateProduct(product); err != nil {
 		if errors.Is(err, entity.ErrProductNotFound) {
 			return nil, status.Errorf(codes.NotFound, "product not found")
 		}
@@ -105,21 +120,27 @@ func (c *ProductController) DeleteProduct(ctx context.Context, req *pb.DeletePro
 
 func (c *ProductController) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
 	filter := entity.ProductFilter{
-		Name:     req.GetName(),
-		Category: req.GetCategory(),
-		MinPrice: req.GetMinPrice(),
-		MaxPrice: req.GetMaxPrice(),
-		Page:     int(req.GetPage()),
-		Limit:    int(req.GetLimit()),
+		Name:         req.GetName(),
+		Category:     req.GetCategory(),
+		MinPrice:     req.GetMinPrice(),
+		MaxPrice:     req.GetMaxPrice(),
+		InStockOnly:  req.GetInStockOnly(),
+		OrderBy:      req.GetOrderBy(),
+		Limit:        int(req.GetLimit()),
+		PageToken:    req.GetPageToken(),
+		IncludeTotal: req.GetIncludeTotalCount(),
 	}
 
-	products, err := c.productUseCase.ListProducts(filter)
+	page, err := c.productUseCase.ListProducts(filter)
 	if err != nil {
+		if errors.Is(err, paging.ErrInvalidToken) || errors.Is(err, paging.ErrInvalidOrderBy) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to list products: %v", err)
 	}
 
 	var productResponses []*pb.ProductResponse
-	for _, product := range products {
+	for _, product := range page.Products {
 		productResponses = append(productResponses, &pb.ProductResponse{
 			Id:          product.ID,
 			Name:        product.Name,
@@ -130,5 +151,82 @@ func (c *ProductController) ListProducts(ctx context.Context, req *pb.ListProduc
 		})
 	}
 
-	return &pb.ListProductsResponse{Products: productResponses}, nil
+	return &pb.ListProductsResponse{
+		Products:      productResponses,
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
+	}, nil
+}
+
+func (c *ProductController) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	search := entity.ProductSearch{
+		Query:       req.GetQuery(),
+		Category:    req.GetCategory(),
+		MinPrice:    req.GetMinPrice(),
+		MaxPrice:    req.GetMaxPrice(),
+		InStockOnly: req.GetInStockOnly(),
+		Limit:       int(req.GetLimit()),
+		PageToken:   req.GetPageToken(),
+	}
+
+	result, err := c.productUseCase.SearchProducts(search)
+	if err != nil {
+		if errors.Is(err, entity.ErrEmptySearchQuery) || errors.Is(err, paging.ErrInvalidToken) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to search products: %v", err)
+	}
+
+	res := &pb.SearchProductsResponse{
+		NextPageToken: result.NextPageToken,
+		TotalCount:    result.TotalCount,
+	}
+	for _, hit := range result.Hits {
+		res.Hits = append(res.Hits, &pb.SearchHit{
+			Product: &pb.ProductResponse{
+				Id:          hit.ID,
+				Name:        hit.Name,
+				Description: hit.Description,
+				Price:       hit.Price,
+				Stock:       int32(hit.Stock),
+				Category:    hit.Category,
+			},
+			Score: hit.Score,
+		})
+	}
+	for _, category := range result.Categories {
+		res.Categories = append(res.Categories, &pb.FacetCount{Value: category.Value, Count: category.Count})
+	}
+	for _, bucket := range result.PriceBuckets {
+		res.PriceBuckets = append(res.PriceBuckets, &pb.PriceBucket{Min: bucket.Min, Max: bucket.Max, Count: bucket.Count})
+	}
+	return res, nil
+}
+
+func (c *ProductController) SuggestProducts(ctx context.Context, req *pb.SuggestProductsRequest) (*pb.SuggestProductsResponse, error) {
+	suggestions, err := c.productUseCase.SuggestProducts(req.GetPrefix(), int(req.GetLimit()))
+	if err != nil {
+		if errors.Is(err, entity.ErrEmptySuggestPrefix) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to suggest products: %v", err)
+	}
+
+	res := &pb.SuggestProductsResponse{}
+	for _, product := range suggestions.Products {
+		res.Products = append(res.Products, &pb.ProductSuggestion{
+			Id:         product.ID,
+			Name:       product.Name,
+			Category:   product.Category,
+			Popularity: product.Popularity,
+		})
+	}
+	for _, category := range suggestions.Categories {
+		res.Categories = append(res.Categories, &pb.CategorySuggestion{
+			Name:       category.Name,
+			Products:   int32(category.Products),
+			Popularity: category.Popularity,
+		})
+	}
+	return res, nil
 }
\ No newline at end of file
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"inventory-service/internal/entity"
+	pb "inventory-service/proto"
+)
+
+func (c *ProductController) ReserveStock(ctx context.Context, req *pb.ReserveRequest) (*pb.ReserveResponse, error) {
+	if req.GetProductId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "product id is required")
+	}
+
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+	reservation, err := c.reservationUseCase.ReserveStock(req.GetProductId(), req.GetOrderId(), int(req.GetQuantity()), ttl)
+	if err != nil {
+		return nil, reservationError(err, "failed to reserve stock")
+	}
+
+	return &pb.ReserveResponse{
+		Success:       true,
+		Message:       "stock reserved",
+		ReservationId: reservation.ID,
+		ExpiresAt:     reservation.ExpiresAt,
+	}, nil
+}
+
+func (c *ProductController) ReleaseStock(ctx context.Context, req *pb.ReleaseRequest) (*pb.ReleaseResponse, error) {
+	if err := c.reservationUseCase.ReleaseStock(req.GetReservationId()); err != nil {
+		return nil, reservationError(err, "failed to release stock")
+	}
+
+	return &pb.ReleaseResponse{Success: true}, nil
+}
+
+func (c *ProductController) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.CommitReservationResponse, error) {
+	if err := c.reservationUseCase.CommitReservation(req.GetReservationId()); err != nil {
+		return nil, reservationError(err, "failed to commit reservation")
+	}
+
+	return &pb.CommitReservationResponse{Success: true}, nil
+}
+
+func (c *ProductController) ReturnStock(ctx context.Context, req *pb.ReturnStockRequest) (*pb.ReturnStockResponse, error) {
+	if err := c.reservationUseCase.ReturnStock(req.GetReservationId()); err != nil {
+		return nil, reservationError(err, "failed to return stock")
+	}
+
+	return &pb.ReturnStockResponse{Success: true}, nil
+}
+
+func reservationError(err error, msg string) error {
+	switch {
+	case errors.Is(err, entity.ErrInvalidQuantity):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, entity.ErrProductNotFound):
+		return status.Errorf(codes.NotFound, "product not found")
+	case errors.Is(err, entity.ErrReservationNotFound):
+		return status.Errorf(codes.NotFound, "reservation not found")
+	case errors.Is(err, entity.ErrInsufficientStock):
+		return status.Errorf(codes.FailedPrecondition, "insufficient stock")
+	case errors.Is(err, entity.ErrReservationNotActive):
+		return status.Errorf(codes.FailedPrecondition, "reservation is no longer active")
+	case errors.Is(err, entity.ErrReservationExists):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
@@ -16,10 +16,93 @@ type ProductFilter struct {
 	Category string
 	MinPrice float64
 	MaxPrice float64
-	Page     int
-	Limit    int
+	// InStockOnly leaves out products with no stock left.
+	InStockOnly bool
+	// OrderBy is one of ProductSortFields, optionally followed by "asc" or
+	// "desc".
+	OrderBy string
+	Limit   int
+	// PageToken continues a listing where the previous page ended.
+	PageToken string
+	// IncludeTotal asks for the number of matching products on all pages.
+	IncludeTotal bool
+}
+
+// ProductPage is one page of a product listing. NextPageToken is empty on
+// the last page and TotalCount is only set when it was asked for.
+type ProductPage struct {
+	Products      []Product
+	NextPageToken string
+	TotalCount    *int64
+}
+
+// ProductSearch is a full-text product search. Results are ordered by
+// relevance to Query.
+type ProductSearch struct {
+	Query       string
+	Category    string
+	MinPrice    float64
+	MaxPrice    float64
+	InStockOnly bool
+	Limit       int
+	// PageToken continues a search where the previous page ended.
+	PageToken string
+}
+
+// SearchHit is a product found by a search and how well it matched.
+type SearchHit struct {
+	Product
+	Score float64
+}
+
+// FacetCount is the number of search results sharing a value.
+type FacetCount struct {
+	Value string
+	Count int64
+}
+
+// PriceBucket counts the search results priced from Min up to, but not
+// including, Max. Max is 0 for the last bucket, which has no upper bound.
+type PriceBucket struct {
+	Min   float64
+	Max   float64
+	Count int64
+}
+
+// SearchResult is one page of search hits, with the total and facet counts
+// of all matching products.
+type SearchResult struct {
+	Hits          []SearchHit
+	NextPageToken string
+	TotalCount    int64
+	Categories    []FacetCount
+	PriceBuckets  []PriceBucket
+}
+
+// PriceBucketBounds are the lower bounds of the price buckets search results
+// are counted in.
+var PriceBucketBounds = []float64{0, 10, 25, 50, 100, 250, 500, 1000}
+
+// ProductSortFields maps the fields products can be ordered by to their bson
+// fields. Product IDs are ObjectIDs, so sorting by ID sorts by creation time.
+var ProductSortFields = map[string]string{
+	"price":      "price",
+	"name":       "name",
+	"stock":      "stock",
+	"created_at": "_id",
+}
+
+// ProductUpdatableFields lists the field mask paths a partial update may name.
+var ProductUpdatableFields = map[string]bool{
+	"name":        true,
+	"description": true,
+	"price":       true,
+	"stock":       true,
+	"category":    true,
 }
 
 var (
-	ErrProductNotFound = errors.New("product not found")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+	ErrEmptySearchQuery  = errors.New("search query is required")
 )
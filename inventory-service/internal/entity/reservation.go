@@ -0,0 +1,36 @@
+package entity
+
+import "errors"
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"
+	ReservationStatusCommitted ReservationStatus = "committed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+	ReservationStatusReturned  ReservationStatus = "returned"
+)
+
+// Reservation holds stock that has already been taken off a product but is not
+// yet final. Committing it makes the decrement permanent; releasing it, or
+// letting it pass ExpiresAt, puts the quantity back on the product. Stock of a
+// committed reservation can still be returned, e.g. for a cancelled order.
+type Reservation struct {
+	ID        string            `bson:"_id,omitempty"`
+	ProductID string            `bson:"product_id"`
+	OrderID   string            `bson:"order_id"`
+	Quantity  int               `bson:"quantity"`
+	Status    ReservationStatus `bson:"status"`
+	ExpiresAt int64             `bson:"expires_at"`
+	CreatedAt int64             `bson:"created_at"`
+	UpdatedAt int64             `bson:"updated_at"`
+}
+
+var (
+	ErrInsufficientStock    = errors.New("insufficient stock")
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationNotActive = errors.New("reservation is no longer active")
+	ErrReservationExists    = errors.New("order already holds a reservation for the product")
+	ErrInvalidQuantity      = errors.New("quantity must be positive")
+)
@@ -0,0 +1,45 @@
+package entity
+
+import "errors"
+
+const (
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 50
+)
+
+var ErrEmptySuggestPrefix = errors.New("prefix is required")
+
+// ProductSuggestion is a product whose name starts with, or has a word
+// starting with, the typed prefix. Popularity is the number of units sold.
+type ProductSuggestion struct {
+	ID         string
+	Name       string
+	Category   string
+	Popularity int64
+}
+
+// CategorySuggestion is a category matching the typed prefix. Popularity is
+// the number of units sold across its products.
+type CategorySuggestion struct {
+	Name       string
+	Products   int
+	Popularity int64
+}
+
+// Suggestions are the completions for a prefix, most popular first.
+type Suggestions struct {
+	Products   []ProductSuggestion
+	Categories []CategorySuggestion
+}
+
+// SuggestLimit turns a requested limit into the number of suggestions of
+// each kind to return.
+func SuggestLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultSuggestLimit
+	}
+	if limit > MaxSuggestLimit {
+		return MaxSuggestLimit
+	}
+	return limit
+}
@@ -6,6 +6,7 @@ import (
     "time"
 
     "inventory-service/internal/entity"
+    "paging"
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/bson/primitive"
     "go.mongodb.org/mongo-driver/mongo"
@@ -16,8 +17,10 @@ type ProductRepository interface {
     Create(product *entity.Product) error
     FindByID(id string) (*entity.Product, error)
     Update(product *entity.Product) error
+    UpdateFields(product *entity.Product, fields []string) (*entity.Product, error)
     Delete(id string) error
-    FindAll(filter entity.ProductFilter) ([]entity.Product, error)
+    FindAll(filter entity.ProductFilter) (*entity.ProductPage, error)
+    Search(search entity.ProductSearch) (*entity.SearchResult, error)
 }
 
 type productRepository struct {
@@ -35,8 +38,14 @@ func (r *productRepository) Create(product *entity.Product) error {
     defer cancel()
 
     log.Printf("[MongoDB] Inserting product: %+v", product)
-    _, err := r.collection.InsertOne(ctx, product)
-    return err
+    res, err := r.collection.InsertOne(ctx, product)
+    if err != nil {
+        return err
+    }
+    if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+        product.ID = oid.Hex()
+    }
+    return nil
 }
 
 func (r *productRepository) FindByID(id string) (*entity.Product, error) {
@@ -82,6 +91,43 @@ func (r *productRepository) Update(product *entity.Product) error {
     return err
 }
 
+// UpdateFields sets only the named fields from product and returns the
+// resulting document.
+func (r *productRepository) UpdateFields(product *entity.Product, fields []string) (*entity.Product, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+
+    objectID, err := primitive.ObjectIDFromHex(product.ID)
+    if err != nil {
+        return nil, err
+    }
+
+    values := bson.M{
+        "name":        product.Name,
+        "description": product.Description,
+        "price":       product.Price,
+        "stock":       product.Stock,
+        "category":    product.Category,
+    }
+    set := bson.M{}
+    for _, field := range fields {
+        set[field] = values[field]
+    }
+
+    log.Printf("[MongoDB] Updating fields %v of product ID: %s", fields, product.ID)
+    opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+    var updated entity.Product
+    err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": objectID}, bson.M{"$set": set}, opts).Decode(&updated)
+    if err != nil {
+        if err == mongo.ErrNoDocuments {
+            return nil, entity.ErrProductNotFound
+        }
+        return nil, err
+    }
+
+    return &updated, nil
+}
+
 func (r *productRepository) Delete(id string) error {
     ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
     defer cancel()
@@ -96,10 +142,50 @@ func (r *productRepository) Delete(id string) error {
     return err
 }
 
-func (r *productRepository) FindAll(filter entity.ProductFilter) ([]entity.Product, error) {
+// EnsureProductIndexes creates the indexes product listings filter and sort
+// on, and the text index behind Search. Every sort index ends in _id, the
+// tie-breaker of all orderings.
+func EnsureProductIndexes(db *mongo.Database) error {
     ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
     defer cancel()
 
+    _, err := db.Collection("products").Indexes().CreateMany(ctx, []mongo.IndexModel{
+        {Keys: bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}},
+        {Keys: bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}},
+        {Keys: bson.D{{Key: "stock", Value: 1}, {Key: "_id", Value: 1}}},
+        {Keys: bson.D{{Key: "category", Value: 1}, {Key: "price", Value: 1}, {Key: "_id", Value: 1}}},
+        {
+            Keys: bson.D{
+                {Key: "name", Value: "text"},
+                {Key: "description", Value: "text"},
+                {Key: "category", Value: "text"},
+            },
+            // A word in the name says more about a product than one in
+            // its description
+            Options: options.Index().
+                SetName("product_text").
+                SetWeights(bson.D{
+                    {Key: "name", Value: 10},
+                    {Key: "category", Value: 5},
+                    {Key: "description", Value: 1},
+                }),
+        },
+    })
+    return err
+}
+
+// FindAll returns one page of the products matching filter in the order it
+// asks for. Ties are broken by ID, so pages neither skip nor repeat products
+// when others are added.
+func (r *productRepository) FindAll(filter entity.ProductFilter) (*entity.ProductPage, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+
+    sort, err := paging.ParseSort(filter.OrderBy, entity.ProductSortFields)
+    if err != nil {
+        return nil, err
+    }
+
     query := bson.M{}
     if filter.Name != "" {
         query["name"] = bson.M{"$regex": filter.Name, "$options": "i"}
@@ -117,24 +203,220 @@ func (r *productRepository) FindAll(filter entity.ProductFilter) ([]entity.Produ
         }
         query["price"] = priceQuery
     }
+    if filter.InStockOnly {
+        query["stock"] = bson.M{"$gt": 0}
+    }
 
-    opts := options.Find()
-    if filter.Limit > 0 {
-        opts.SetLimit(int64(filter.Limit))
-        opts.SetSkip(int64((filter.Page - 1) * filter.Limit))
+    page := &entity.ProductPage{}
+    if filter.IncludeTotal {
+        total, err := r.collection.CountDocuments(ctx, query)
+        if err != nil {
+            return nil, err
+        }
+        page.TotalCount = &total
+    }
+
+    pageQuery := query
+    if filter.PageToken != "" {
+        after, err := sort.After(filter.PageToken)
+        if err != nil {
+            return nil, err
+        }
+        pageQuery = bson.M{"$and": bson.A{query, after}}
     }
 
+    // Fetch one extra product to learn whether another page follows
+    size := paging.Size(filter.Limit)
+    opts := options.Find().
+        SetSort(sort.Order()).
+        SetLimit(int64(size + 1))
+
     log.Printf("[MongoDB] Finding all products with filter: %+v", filter)
-    cursor, err := r.collection.Find(ctx, query, opts)
+    cursor, err := r.collection.Find(ctx, pageQuery, opts)
     if err != nil {
         return nil, err
     }
     defer cursor.Close(ctx)
 
-    var products []entity.Product
-    if err = cursor.All(ctx, &products); err != nil {
+    if err = cursor.All(ctx, &page.Products); err != nil {
         return nil, err
     }
 
-    return products, nil
+    if len(page.Products) > size {
+        page.Products = page.Products[:size]
+        last := page.Products[size-1]
+        page.NextPageToken = sort.NextToken(last.ID, productSortValue(last, sort))
+    }
+    return page, nil
+}
+
+func productSortValue(product entity.Product, sort paging.Sort) interface{} {
+    switch sort.Field {
+    case "price":
+        return product.Price
+    case "name":
+        return product.Name
+    case "stock":
+        return product.Stock
+    }
+    return nil
+}
+
+// searchFacets is the document the search pipeline's $facet stage returns.
+type searchFacets struct {
+    Hits []struct {
+        entity.Product `bson:",inline"`
+        Score          float64 `bson:"score"`
+    } `bson:"hits"`
+    Total []struct {
+        Count int64 `bson:"count"`
+    } `bson:"total"`
+    Categories []struct {
+        Value string `bson:"_id"`
+        Count int64  `bson:"count"`
+    } `bson:"categories"`
+    Prices []struct {
+        Min   float64 `bson:"_id"`
+        Count int64   `bson:"count"`
+    } `bson:"prices"`
+}
+
+// Search finds the products matching a text query, most relevant first. A
+// single aggregation returns the page of hits together with the total and
+// facet counts of everything that matched.
+func (r *productRepository) Search(search entity.ProductSearch) (*entity.SearchResult, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+    defer cancel()
+
+    match := bson.M{"$text": bson.M{"$search": search.Query}}
+    if search.Category != "" {
+        match["category"] = search.Category
+    }
+    if search.MinPrice > 0 || search.MaxPrice > 0 {
+        priceQuery := bson.M{}
+        if search.MinPrice > 0 {
+            priceQuery["$gte"] = search.MinPrice
+        }
+        if search.MaxPrice > 0 {
+            priceQuery["$lte"] = search.MaxPrice
+        }
+        match["price"] = priceQuery
+    }
+    if search.InStockOnly {
+        match["stock"] = bson.M{"$gt": 0}
+    }
+
+    // Fetch one extra hit to learn whether another page follows
+    size := paging.Size(search.Limit)
+    hits := bson.A{}
+    if search.PageToken != "" {
+        after, err := afterScore(search.PageToken)
+        if err != nil {
+            return nil, err
+        }
+        hits = append(hits, bson.M{"$match": after})
+    }
+    hits = append(hits,
+        bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}},
+        bson.M{"$limit": size + 1},
+    )
+
+    bounds := entity.PriceBucketBounds
+    pipeline := mongo.Pipeline{
+        {{Key: "$match", Value: match}},
+        {{Key: "$addFields", Value: bson.M{"score": bson.M{"$meta": "textScore"}}}},
+        {{Key: "$facet", Value: bson.M{
+            "hits":       hits,
+            "total":      bson.A{bson.M{"$count": "count"}},
+            "categories": bson.A{bson.M{"$sortByCount": "$category"}},
+            "prices": bson.A{bson.M{"$bucket": bson.M{
+                "groupBy":    "$price",
+                "boundaries": bounds,
+                // Prices from the last bound up fall outside the
+                // boundaries and are counted in the default bucket, which
+                // shares the last bound as its ID
+                "default": bounds[len(bounds)-1],
+            }}},
+        }}},
+    }
+
+    log.Printf("[MongoDB] Searching products: %+v", search)
+    cursor, err := r.collection.Aggregate(ctx, pipeline)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var facets []searchFacets
+    if err = cursor.All(ctx, &facets); err != nil {
+        return nil, err
+    }
+
+    result := &entity.SearchResult{}
+    if len(facets) == 0 {
+        return result, nil
+    }
+    found := facets[0]
+
+    for _, hit := range found.Hits {
+        result.Hits = append(result.Hits, entity.SearchHit{Product: hit.Product, Score: hit.Score})
+    }
+    if len(result.Hits) > size {
+        result.Hits = result.Hits[:size]
+        last := result.Hits[size-1]
+        result.NextPageToken = paging.EncodeToken(paging.Cursor{
+            OrderBy: relevanceOrder,
+            Value:   last.Score,
+            AfterID: last.ID,
+        })
+    }
+    if len(found.Total) > 0 {
+        result.TotalCount = found.Total[0].Count
+    }
+    for _, category := range found.Categories {
+        result.Categories = append(result.Categories, entity.FacetCount{Value: category.Value, Count: category.Count})
+    }
+    for _, bucket := range found.Prices {
+        result.PriceBuckets = append(result.PriceBuckets, entity.PriceBucket{
+            Min:   bucket.Min,
+            Max:   priceBucketMax(bucket.Min),
+            Count: bucket.Count,
+        })
+    }
+    return result, nil
+}
+
+// relevanceOrder is the ordering search page tokens are issued for.
+const relevanceOrder = "relevance"
+
+// afterScore returns the condition matching the search hits that rank after
+// the page token: a lower score, or the same score and a higher ID.
+func afterScore(token string) (bson.M, error) {
+    cursor, err := paging.DecodeToken(token)
+    if err != nil {
+        return nil, err
+    }
+    score, ok := cursor.Value.(float64)
+    if cursor.OrderBy != relevanceOrder || !ok {
+        return nil, paging.ErrInvalidToken
+    }
+    afterID, err := primitive.ObjectIDFromHex(cursor.AfterID)
+    if err != nil {
+        return nil, paging.ErrInvalidToken
+    }
+    return bson.M{"$or": bson.A{
+        bson.M{"score": bson.M{"$lt": score}},
+        bson.M{"score": score, "_id": bson.M{"$gt": afterID}},
+    }}, nil
+}
+
+// priceBucketMax returns the upper bound of the price bucket starting at min,
+// or 0 for the last bucket.
+func priceBucketMax(min float64) float64 {
+    for _, bound := range entity.PriceBucketBounds {
+        if bound > min {
+            return bound
+        }
+    }
+    return 0
 }
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-service/internal/entity"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ReservationRepository interface {
+	Reserve(reservation *entity.Reservation) error
+	FindByID(id string) (*entity.Reservation, error)
+	FindHeld(orderID, productID string) (*entity.Reservation, error)
+	Transition(id string, from, to entity.ReservationStatus, notExpiredAt int64) (*entity.Reservation, error)
+	Settle(id string, from, to entity.ReservationStatus) (*entity.Reservation, error)
+	FindExpired(now int64, limit int) ([]entity.Reservation, error)
+	UnitsSold() (map[string]int64, error)
+}
+
+// reservationRepository changes stock and reservations together in one
+// transaction, so stock is never taken without a reservation to give it
+// back, nor given back twice.
+type reservationRepository struct {
+	collection *mongo.Collection
+	products   *mongo.Collection
+	client     *mongo.Client // For transaction support
+}
+
+func NewReservationRepository(db *mongo.Database) ReservationRepository {
+	return &reservationRepository{
+		collection: db.Collection("reservations"),
+		products:   db.Collection("products"),
+		client:     db.Client(),
+	}
+}
+
+// EnsureReservationIndexes lets an order hold at most one reservation per
+// product, which makes concurrent retries of ReserveStock safe, and backs
+// the expiry sweep. The partial filter uses $in, which needs MongoDB 6.0.
+func EnsureReservationIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.Collection("reservations").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "order_id", Value: 1}, {Key: "product_id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{
+					"order_id": bson.M{"$gt": ""},
+					"status": bson.M{"$in": bson.A{
+						entity.ReservationStatusActive,
+						entity.ReservationStatusCommitted,
+					}},
+				}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+	})
+	return err
+}
+
+// Reserve takes the reservation's quantity off its product and stores the
+// reservation, both or neither. It returns ErrReservationExists when the
+// order already holds stock of the product.
+func (r *reservationRepository) Reserve(reservation *entity.Reservation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	productID, err := primitive.ObjectIDFromHex(reservation.ProductID)
+	if err != nil {
+		return entity.ErrProductNotFound
+	}
+
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	log.Printf("[MongoDB] Reserving %d of product %s for order %s", reservation.Quantity, reservation.ProductID, reservation.OrderID)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		filter := bson.M{"_id": productID, "stock": bson.M{"$gte": reservation.Quantity}}
+		res, err := r.products.UpdateOne(sessCtx, filter, bson.M{"$inc": bson.M{"stock": -reservation.Quantity}})
+		if err != nil {
+			return nil, err
+		}
+		if res.MatchedCount == 0 {
+			count, err := r.products.CountDocuments(sessCtx, bson.M{"_id": productID})
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, entity.ErrProductNotFound
+			}
+			return nil, entity.ErrInsufficientStock
+		}
+
+		// The callback may be retried, so let MongoDB assign a fresh ID each time
+		reservation.ID = ""
+		inserted, err := r.collection.InsertOne(sessCtx, reservation)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return nil, entity.ErrReservationExists
+			}
+			return nil, err
+		}
+		if oid, ok := inserted.InsertedID.(primitive.ObjectID); ok {
+			reservation.ID = oid.Hex()
+		}
+		return nil, nil
+	})
+	return err
+}
+
+func (r *reservationRepository) FindByID(id string) (*entity.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, entity.ErrReservationNotFound
+	}
+
+	var reservation entity.Reservation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindHeld returns the active or committed reservation an order holds on a
+// product, or ErrReservationNotFound when there is none.
+func (r *reservationRepository) FindHeld(orderID, productID string) (*entity.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"order_id":   orderID,
+		"product_id": productID,
+		"status": bson.M{"$in": bson.A{
+			entity.ReservationStatusActive,
+			entity.ReservationStatusCommitted,
+		}},
+	}
+
+	var reservation entity.Reservation
+	err := r.collection.FindOne(ctx, filter).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Transition moves a reservation from one status to another in a single
+// conditional update, so only one caller can ever win a given transition.
+// When notExpiredAt is non-zero the reservation must also expire after it.
+// The returned reservation reflects the state before the update.
+func (r *reservationRepository) Transition(id string, from, to entity.ReservationStatus, notExpiredAt int64) (*entity.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.transition(ctx, id, from, to, notExpiredAt)
+}
+
+// Settle moves a reservation to a final status and puts its quantity back
+// on the product in the same transaction. The stock of a product that has
+// been deleted meanwhile is simply dropped.
+func (r *reservationRepository) Settle(id string, from, to entity.ReservationStatus) (*entity.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := r.client.StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	var reservation *entity.Reservation
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var err error
+		reservation, err = r.transition(sessCtx, id, from, to, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		productID, err := primitive.ObjectIDFromHex(reservation.ProductID)
+		if err != nil {
+			return nil, nil
+		}
+		log.Printf("[MongoDB] Incrementing stock of product %s by %d", reservation.ProductID, reservation.Quantity)
+		_, err = r.products.UpdateByID(sessCtx, productID, bson.M{"$inc": bson.M{"stock": reservation.Quantity}})
+		return nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (r *reservationRepository) transition(ctx context.Context, id string, from, to entity.ReservationStatus, notExpiredAt int64) (*entity.Reservation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, entity.ErrReservationNotFound
+	}
+
+	filter := bson.M{"_id": objectID, "status": from}
+	if notExpiredAt > 0 {
+		filter["expires_at"] = bson.M{"$gt": notExpiredAt}
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     to,
+			"updated_at": time.Now().Unix(),
+		},
+	}
+
+	var reservation entity.Reservation
+	err = r.collection.FindOneAndUpdate(ctx, filter, update).Decode(&reservation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			if _, findErr := r.FindByID(id); findErr != nil {
+				return nil, findErr
+			}
+			return nil, entity.ErrReservationNotActive
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) FindExpired(now int64, limit int) ([]entity.Reservation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	query := bson.M{
+		"status":     entity.ReservationStatusActive,
+		"expires_at": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetSort(bson.M{"expires_at": 1}).SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reservations []entity.Reservation
+	if err = cursor.All(ctx, &reservations); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// UnitsSold returns, per product ID, the quantity held by committed
+// reservations: what was sold and not returned.
+func (r *reservationRepository) UnitsSold() (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": entity.ReservationStatusCommitted}}},
+		{{Key: "$group", Value: bson.M{"_id": "$product_id", "sold": bson.M{"$sum": "$quantity"}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals []struct {
+		ProductID string `bson:"_id"`
+		Sold      int64  `bson:"sold"`
+	}
+	if err = cursor.All(ctx, &totals); err != nil {
+		return nil, err
+	}
+
+	sold := make(map[string]int64, len(totals))
+	for _, total := range totals {
+		sold[total.ProductID] = total.Sold
+	}
+	return sold, nil
+}
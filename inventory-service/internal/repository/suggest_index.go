@@ -0,0 +1,423 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"inventory-service/internal/entity"
+)
+
+// SuggestIndex is an in-memory prefix index over product names and
+// categories, used for search-as-you-type. Each instance of the service
+// keeps its own: it is built at startup and only updated by the writes that
+// instance handles, so products created, changed or sold through another
+// replica are not suggested here until this one restarts.
+type SuggestIndex interface {
+	// Load replaces the indexed products with products in one pass. Units
+	// sold recorded beforehand are kept.
+	Load(products []entity.Product)
+	Put(product *entity.Product)
+	Remove(id string)
+	// AddUnitsSold changes the popularity of a product; quantity is
+	// negative for returned stock.
+	AddUnitsSold(productID string, quantity int)
+	Suggest(prefix string, limit int) entity.Suggestions
+}
+
+// suggestTopK is how many entries every trie node ranks: enough for the
+// largest limit Suggest is called with.
+const suggestTopK = entity.MaxSuggestLimit
+
+type categoryStats struct {
+	products int
+	sold     int64
+}
+
+type suggestIndex struct {
+	mu         sync.RWMutex
+	products   map[string]entity.Product
+	sold       map[string]int64
+	categories map[string]*categoryStats
+	// productTrie is keyed by product ID and categoryTrie by category name.
+	productTrie  *prefixTrie
+	categoryTrie *prefixTrie
+}
+
+func NewSuggestIndex() SuggestIndex {
+	x := &suggestIndex{
+		products:   make(map[string]entity.Product),
+		sold:       make(map[string]int64),
+		categories: make(map[string]*categoryStats),
+	}
+	x.productTrie = newPrefixTrie(x.productBefore)
+	x.categoryTrie = newPrefixTrie(x.categoryBefore)
+	return x
+}
+
+func (x *suggestIndex) Load(products []entity.Product) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.products = make(map[string]entity.Product, len(products))
+	x.categories = make(map[string]*categoryStats)
+	x.productTrie = newPrefixTrie(x.productBefore)
+	x.categoryTrie = newPrefixTrie(x.categoryBefore)
+
+	for i := range products {
+		product := &products[i]
+		if product.ID == "" {
+			continue
+		}
+		if _, ok := x.products[product.ID]; ok {
+			continue
+		}
+		x.products[product.ID] = entity.Product{ID: product.ID, Name: product.Name, Category: product.Category}
+		for _, term := range suggestTerms(product.Name) {
+			x.productTrie.insert(term, product.ID)
+		}
+		if product.Category == "" {
+			continue
+		}
+		stats := x.categories[product.Category]
+		if stats == nil {
+			stats = &categoryStats{}
+			x.categories[product.Category] = stats
+			for _, term := range suggestTerms(product.Category) {
+				x.categoryTrie.insert(term, product.Category)
+			}
+		}
+		stats.products++
+		stats.sold += x.sold[product.ID]
+	}
+
+	x.productTrie.rankAll()
+	x.categoryTrie.rankAll()
+}
+
+func (x *suggestIndex) Put(product *entity.Product) {
+	if product.ID == "" {
+		return
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(product.ID)
+	x.products[product.ID] = entity.Product{ID: product.ID, Name: product.Name, Category: product.Category}
+	for _, term := range suggestTerms(product.Name) {
+		x.productTrie.insert(term, product.ID)
+		x.productTrie.rerank(term)
+	}
+	if product.Category == "" {
+		return
+	}
+	stats := x.categories[product.Category]
+	if stats == nil {
+		stats = &categoryStats{}
+		x.categories[product.Category] = stats
+		for _, term := range suggestTerms(product.Category) {
+			x.categoryTrie.insert(term, product.Category)
+		}
+	}
+	stats.products++
+	stats.sold += x.sold[product.ID]
+	x.rerankCategory(product.Category)
+}
+
+func (x *suggestIndex) Remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.remove(id)
+	delete(x.sold, id)
+}
+
+func (x *suggestIndex) AddUnitsSold(productID string, quantity int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.sold[productID] += int64(quantity)
+	product, ok := x.products[productID]
+	if !ok {
+		return
+	}
+	for _, term := range suggestTerms(product.Name) {
+		x.productTrie.rerank(term)
+	}
+	if stats := x.categories[product.Category]; stats != nil {
+		stats.sold += int64(quantity)
+		x.rerankCategory(product.Category)
+	}
+}
+
+// Suggest returns up to limit products and up to limit categories matching
+// prefix, most popular first. A name or category matches when it, or one of
+// its words onwards, starts with the prefix; case and repeated spaces are
+// ignored. The rankings are kept up to date on every write, so this only
+// walks down the prefix.
+func (x *suggestIndex) Suggest(prefix string, limit int) entity.Suggestions {
+	var suggestions entity.Suggestions
+	prefix = normalizeTerm(prefix)
+	if prefix == "" {
+		return suggestions
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	for _, id := range x.productTrie.top(prefix, limit) {
+		product := x.products[id]
+		suggestions.Products = append(suggestions.Products, entity.ProductSuggestion{
+			ID:         product.ID,
+			Name:       product.Name,
+			Category:   product.Category,
+			Popularity: x.sold[id],
+		})
+	}
+	for _, name := range x.categoryTrie.top(prefix, limit) {
+		stats := x.categories[name]
+		suggestions.Categories = append(suggestions.Categories, entity.CategorySuggestion{
+			Name:       name,
+			Products:   stats.products,
+			Popularity: stats.sold,
+		})
+	}
+	return suggestions
+}
+
+// remove drops a product's terms and category count. Its units sold are
+// kept, as a product is removed and put back on every update. Terms are
+// deleted before the product or category itself, since re-ranking after one
+// term may still meet the others.
+func (x *suggestIndex) remove(id string) {
+	product, ok := x.products[id]
+	if !ok {
+		return
+	}
+	for _, term := range suggestTerms(product.Name) {
+		x.productTrie.delete(term, id)
+	}
+	delete(x.products, id)
+
+	stats := x.categories[product.Category]
+	if stats == nil {
+		return
+	}
+	stats.products--
+	stats.sold -= x.sold[id]
+	if stats.products > 0 {
+		x.rerankCategory(product.Category)
+		return
+	}
+	for _, term := range suggestTerms(product.Category) {
+		x.categoryTrie.delete(term, product.Category)
+	}
+	delete(x.categories, product.Category)
+}
+
+func (x *suggestIndex) rerankCategory(name string) {
+	for _, term := range suggestTerms(name) {
+		x.categoryTrie.rerank(term)
+	}
+}
+
+// productBefore ranks products by units sold, then by name.
+func (x *suggestIndex) productBefore(a, b string) bool {
+	if x.sold[a] != x.sold[b] {
+		return x.sold[a] > x.sold[b]
+	}
+	if nameA, nameB := x.products[a].Name, x.products[b].Name; nameA != nameB {
+		return nameA < nameB
+	}
+	return a < b
+}
+
+// categoryBefore ranks categories by units sold across their products, then
+// by name.
+func (x *suggestIndex) categoryBefore(a, b string) bool {
+	soldA, soldB := x.categories[a].sold, x.categories[b].sold
+	if soldA != soldB {
+		return soldA > soldB
+	}
+	return a < b
+}
+
+// prefixTrie maps terms to keys. Every node ranks the best suggestTopK keys
+// stored at or below it, so the best completions of a prefix are read off
+// the node the prefix leads to. A node's ranking only depends on its
+// children's rankings and its own keys, so a change to a key only re-ranks
+// the nodes on the paths of its terms.
+type prefixTrie struct {
+	root *trieNode
+	// before is the ranking: it reports whether key a comes before key b.
+	before func(a, b string) bool
+}
+
+type trieNode struct {
+	label    byte
+	children []*trieNode
+	// keys are the keys with a term ending at this node.
+	keys []string
+	// ranked are the best keys at or below this node, best first.
+	ranked []string
+}
+
+func newPrefixTrie(before func(a, b string) bool) *prefixTrie {
+	return &prefixTrie{root: &trieNode{}, before: before}
+}
+
+// insert stores key under term, which it must not already be stored under.
+// The rankings are not updated; call rerank for the term, or rankAll once a
+// bulk load is done.
+func (t *prefixTrie) insert(term, key string) {
+	n := t.root
+	for i := 0; i < len(term); i++ {
+		child := n.child(term[i])
+		if child == nil {
+			child = &trieNode{label: term[i]}
+			n.children = append(n.children, child)
+		}
+		n = child
+	}
+	n.keys = append(n.keys, key)
+}
+
+// delete removes key from term, drops the nodes left empty and re-ranks the
+// rest of the path.
+func (t *prefixTrie) delete(term, key string) {
+	path := t.path(term)
+	if len(path) != len(term)+1 {
+		return
+	}
+	last := path[len(path)-1]
+	for i, k := range last.keys {
+		if k == key {
+			last.keys = append(last.keys[:i], last.keys[i+1:]...)
+			break
+		}
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		n := path[i]
+		if i > 0 && len(n.keys) == 0 && len(n.children) == 0 {
+			path[i-1].removeChild(n.label)
+			continue
+		}
+		t.rank(n)
+	}
+}
+
+// rerank updates the rankings on the path of term, deepest node first.
+func (t *prefixTrie) rerank(term string) {
+	path := t.path(term)
+	for i := len(path) - 1; i >= 0; i-- {
+		t.rank(path[i])
+	}
+}
+
+// rankAll ranks every node, children before their parents.
+func (t *prefixTrie) rankAll() {
+	var walk func(n *trieNode)
+	walk = func(n *trieNode) {
+		for _, child := range n.children {
+			walk(child)
+		}
+		t.rank(n)
+	}
+	walk(t.root)
+}
+
+// top returns up to limit of the best keys stored under prefix.
+func (t *prefixTrie) top(prefix string, limit int) []string {
+	path := t.path(prefix)
+	if len(path) != len(prefix)+1 {
+		return nil
+	}
+	ranked := path[len(path)-1].ranked
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
+
+// path returns the nodes from the root down along term, stopping early where
+// term leaves the trie.
+func (t *prefixTrie) path(term string) []*trieNode {
+	path := []*trieNode{t.root}
+	n := t.root
+	for i := 0; i < len(term); i++ {
+		n = n.child(term[i])
+		if n == nil {
+			break
+		}
+		path = append(path, n)
+	}
+	return path
+}
+
+// rank merges the node's own keys with its children's rankings. A popular
+// term can end at thousands of keys, so this keeps the best suggestTopK as it
+// goes rather than sorting them all.
+func (t *prefixTrie) rank(n *trieNode) {
+	ranked := make([]string, 0, suggestTopK+1)
+	add := func(key string) {
+		if len(ranked) == suggestTopK && !t.before(key, ranked[len(ranked)-1]) {
+			return
+		}
+		i := sort.Search(len(ranked), func(i int) bool { return t.before(key, ranked[i]) })
+		// A key with several terms under this node is offered more than
+		// once; the ranking is total, so a copy sits right before i
+		if i > 0 && ranked[i-1] == key {
+			return
+		}
+		ranked = append(ranked, "")
+		copy(ranked[i+1:], ranked[i:])
+		ranked[i] = key
+		if len(ranked) > suggestTopK {
+			ranked = ranked[:suggestTopK]
+		}
+	}
+	for _, key := range n.keys {
+		add(key)
+	}
+	for _, child := range n.children {
+		for _, key := range child.ranked {
+			add(key)
+		}
+	}
+	n.ranked = ranked
+}
+
+func (n *trieNode) child(label byte) *trieNode {
+	for _, child := range n.children {
+		if child.label == label {
+			return child
+		}
+	}
+	return nil
+}
+
+func (n *trieNode) removeChild(label byte) {
+	for i, child := range n.children {
+		if child.label == label {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// suggestTerms returns the keys text is indexed under: the whole text and
+// the text from each later word on, so "Coffee Mug" is found by "mu" too.
+func suggestTerms(text string) []string {
+	words := strings.Fields(strings.ToLower(text))
+	terms := make([]string, 0, len(words))
+	for i := range words {
+		terms = append(terms, strings.Join(words[i:], " "))
+	}
+	return terms
+}
+
+func normalizeTerm(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"inventory-service/internal/entity"
+)
+
+func productNames(s entity.Suggestions) []string {
+	var names []string
+	for _, p := range s.Products {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func categoryNames(s entity.Suggestions) []string {
+	var names []string
+	for _, c := range s.Categories {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestSuggestMatchesWordsIgnoringCaseAndSpaces(t *testing.T) {
+	x := NewSuggestIndex()
+	x.Put(&entity.Product{ID: "1", Name: "Coffee Mug", Category: "Kitchen"})
+	x.Put(&entity.Product{ID: "2", Name: "Coffee Beans", Category: "Groceries"})
+	x.Put(&entity.Product{ID: "3", Name: "Tea Kettle", Category: "Kitchen"})
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"cof", []string{"Coffee Beans", "Coffee Mug"}},
+		{"  COFFEE   m", []string{"Coffee Mug"}},
+		{"mu", []string{"Coffee Mug"}},
+		{"k", []string{"Tea Kettle"}},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		if got := productNames(x.Suggest(tt.prefix, 10)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) products = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	got := x.Suggest("k", 10).Categories
+	if len(got) != 1 || got[0].Name != "Kitchen" || got[0].Products != 2 {
+		t.Errorf("Suggest(%q) categories = %+v, want Kitchen with 2 products", "k", got)
+	}
+}
+
+func TestSuggestRanksByUnitsSold(t *testing.T) {
+	x := NewSuggestIndex()
+	x.Put(&entity.Product{ID: "1", Name: "Mug Blue", Category: "Mugs"})
+	x.Put(&entity.Product{ID: "2", Name: "Mug Red", Category: "Mugs"})
+	x.Put(&entity.Product{ID: "3", Name: "Mug Green", Category: "Mugware"})
+
+	// Ties are broken by name
+	if got, want := productNames(x.Suggest("mug", 10)), []string{"Mug Blue", "Mug Green", "Mug Red"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("products = %v, want %v", got, want)
+	}
+
+	x.AddUnitsSold("2", 5)
+	x.AddUnitsSold("3", 3)
+	if got, want := productNames(x.Suggest("mug", 10)), []string{"Mug Red", "Mug Green", "Mug Blue"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("products = %v, want %v", got, want)
+	}
+	if got, want := categoryNames(x.Suggest("mug", 10)), []string{"Mugs", "Mugware"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("categories = %v, want %v", got, want)
+	}
+
+	// Returned stock counts against the product
+	x.AddUnitsSold("2", -5)
+	if got, want := productNames(x.Suggest("mug", 2)), []string{"Mug Green", "Mug Blue"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("products = %v, want %v", got, want)
+	}
+	if got, want := categoryNames(x.Suggest("mug", 10)), []string{"Mugware", "Mugs"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("categories = %v, want %v", got, want)
+	}
+}
+
+func TestSuggestFollowsUpdatesAndRemovals(t *testing.T) {
+	x := NewSuggestIndex()
+	x.AddUnitsSold("1", 4)
+	x.Put(&entity.Product{ID: "1", Name: "Desk Lamp", Category: "Lighting"})
+	x.Put(&entity.Product{ID: "2", Name: "Floor Lamp", Category: "Lighting"})
+
+	x.Put(&entity.Product{ID: "1", Name: "Reading Light", Category: "Office"})
+	if got, want := productNames(x.Suggest("lamp", 10)), []string{"Floor Lamp"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after rename, lamp = %v, want %v", got, want)
+	}
+	got := x.Suggest("re", 10)
+	if len(got.Products) != 1 || got.Products[0].Popularity != 4 {
+		t.Fatalf("renamed product = %+v, want its units sold kept", got.Products)
+	}
+	if cats := x.Suggest("li", 10).Categories; len(cats) != 1 || cats[0].Products != 1 || cats[0].Popularity != 0 {
+		t.Fatalf("Lighting = %+v, want 1 product and no units sold", cats)
+	}
+
+	x.Remove("2")
+	if got := x.Suggest("l", 10); len(got.Products) != 1 || len(got.Categories) != 0 {
+		t.Fatalf("after removal, l = %+v, want only Reading Light and no categories", got)
+	}
+}
+
+// referenceSuggest is the brute-force answer Suggest must match.
+func referenceSuggest(products map[string]entity.Product, sold map[string]int64, prefix string, limit int) entity.Suggestions {
+	var s entity.Suggestions
+	prefix = normalizeTerm(prefix)
+	type category struct {
+		products int
+		sold     int64
+	}
+	categories := make(map[string]*category)
+	for _, p := range products {
+		if p.Category != "" {
+			c := categories[p.Category]
+			if c == nil {
+				c = &category{}
+				categories[p.Category] = c
+			}
+			c.products++
+			c.sold += sold[p.ID]
+		}
+		for _, term := range suggestTerms(p.Name) {
+			if strings.HasPrefix(term, prefix) {
+				s.Products = append(s.Products, entity.ProductSuggestion{ID: p.ID, Name: p.Name, Category: p.Category, Popularity: sold[p.ID]})
+				break
+			}
+		}
+	}
+	sort.Slice(s.Products, func(i, j int) bool {
+		a, b := s.Products[i], s.Products[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	if len(s.Products) > limit {
+		s.Products = s.Products[:limit]
+	}
+
+	for name, c := range categories {
+		for _, term := range suggestTerms(name) {
+			if strings.HasPrefix(term, prefix) {
+				s.Categories = append(s.Categories, entity.CategorySuggestion{Name: name, Products: c.products, Popularity: c.sold})
+				break
+			}
+		}
+	}
+	sort.Slice(s.Categories, func(i, j int) bool {
+		a, b := s.Categories[i], s.Categories[j]
+		if a.Popularity != b.Popularity {
+			return a.Popularity > b.Popularity
+		}
+		return a.Name < b.Name
+	})
+	if len(s.Categories) > limit {
+		s.Categories = s.Categories[:limit]
+	}
+	return s
+}
+
+var (
+	testWords      = []string{"coffee", "cup", "mug", "kettle", "tea", "teapot", "mat"}
+	testCategories = []string{"", "Kitchen", "Kitchenware", "Tea Time", "Cups"}
+	testPrefixes   = []string{"c", "co", "cup", "m", "mu", "t", "tea", "teap", "k", "coffee m", "tea t", "z"}
+)
+
+func randomProduct(r *rand.Rand, id string) entity.Product {
+	words := make([]string, 1+r.Intn(3))
+	for i := range words {
+		words[i] = testWords[r.Intn(len(testWords))]
+	}
+	return entity.Product{
+		ID:       id,
+		Name:     strings.Join(words, " "),
+		Category: testCategories[r.Intn(len(testCategories))],
+	}
+}
+
+// More products than suggestTopK match the short prefixes, so this covers
+// products dropping out of and climbing back into the per-node rankings.
+func TestSuggestMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	x := NewSuggestIndex()
+	products := make(map[string]entity.Product)
+	sold := make(map[string]int64)
+
+	check := func(step int) {
+		t.Helper()
+		for _, prefix := range testPrefixes {
+			for _, limit := range []int{3, entity.MaxSuggestLimit} {
+				got := x.Suggest(prefix, limit)
+				want := referenceSuggest(products, sold, prefix, limit)
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("step %d: Suggest(%q, %d) =\n%+v\nwant\n%+v", step, prefix, limit, got, want)
+				}
+			}
+		}
+	}
+
+	// Bulk load, with units sold recorded first as at startup
+	var initial []entity.Product
+	for i := 0; i < 150; i++ {
+		p := randomProduct(r, fmt.Sprintf("p%03d", i))
+		initial = append(initial, p)
+		products[p.ID] = p
+		if n := r.Intn(4); n > 0 {
+			sold[p.ID] += int64(n)
+			x.AddUnitsSold(p.ID, n)
+		}
+	}
+	x.Load(initial)
+	check(0)
+
+	for step := 1; step <= 600; step++ {
+		id := fmt.Sprintf("p%03d", r.Intn(200))
+		switch op := r.Intn(10); {
+		case op < 3:
+			p := randomProduct(r, id)
+			products[id] = p
+			x.Put(&p)
+		case op < 4:
+			delete(products, id)
+			delete(sold, id)
+			x.Remove(id)
+		default:
+			n := r.Intn(7) - 2
+			sold[id] += int64(n)
+			x.AddUnitsSold(id, n)
+		}
+		if step%20 == 0 {
+			check(step)
+		}
+	}
+}
+
+func BenchmarkSuggestOneLetter(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	x := NewSuggestIndex()
+	products := make([]entity.Product, 100000)
+	for i := range products {
+		products[i] = randomProduct(r, fmt.Sprintf("p%06d", i))
+		x.AddUnitsSold(products[i].ID, r.Intn(1000))
+	}
+	x.Load(products)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Suggest("c", entity.MaxSuggestLimit)
+	}
+}
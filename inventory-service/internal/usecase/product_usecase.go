@@ -2,30 +2,41 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"inventory-service/internal/entity"
 	"inventory-service/internal/repository"
+	"paging"
 )
 
 type ProductUseCase struct {
-	productRepo repository.ProductRepository
-	cacheRepo   repository.ProductCacheRepository
+	productRepo  repository.ProductRepository
+	cacheRepo    repository.ProductCacheRepository
+	suggestIndex repository.SuggestIndex
 }
 
 func NewProductUseCase(
 	productRepo repository.ProductRepository,
 	cacheRepo repository.ProductCacheRepository,
+	suggestIndex repository.SuggestIndex,
 ) *ProductUseCase {
 	return &ProductUseCase{
-		productRepo: productRepo,
-		cacheRepo:   cacheRepo,
+		productRepo:  productRepo,
+		cacheRepo:    cacheRepo,
+		suggestIndex: suggestIndex,
 	}
 }
 
 func (uc *ProductUseCase) CreateProduct(product *entity.Product) error {
-	return uc.productRepo.Create(product)
+	if err := uc.productRepo.Create(product); err != nil {
+		return err
+	}
+
+	uc.suggestIndex.Put(product)
+	return nil
 }
 
 func (uc *ProductUseCase) GetProduct(id string) (*entity.Product, error) {
@@ -58,6 +69,7 @@ func (uc *ProductUseCase) UpdateProduct(product *entity.Product) error {
 	if err := uc.productRepo.Update(product); err != nil {
 		return err
 	}
+	uc.suggestIndex.Put(product)
 
 	// Invalidate cache
 	ctx := context.Background()
@@ -68,10 +80,38 @@ func (uc *ProductUseCase) UpdateProduct(product *entity.Product) error {
 	return nil
 }
 
+// UpdateProductFields changes only the fields named in the mask and leaves
+// the rest of the stored product untouched.
+func (uc *ProductUseCase) UpdateProductFields(product *entity.Product, fields []string) (*entity.Product, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no fields given", entity.ErrInvalidUpdateMask)
+	}
+	for _, field := range fields {
+		if !entity.ProductUpdatableFields[field] {
+			return nil, fmt.Errorf("%w: unknown field %q", entity.ErrInvalidUpdateMask, field)
+		}
+	}
+
+	updated, err := uc.productRepo.UpdateFields(product, fields)
+	if err != nil {
+		return nil, err
+	}
+	uc.suggestIndex.Put(updated)
+
+	// Invalidate cache
+	ctx := context.Background()
+	if err := uc.cacheRepo.DeleteProduct(ctx, product.ID); err != nil {
+		log.Printf("Failed to invalidate cache for product %s: %v", product.ID, err)
+	}
+
+	return updated, nil
+}
+
 func (uc *ProductUseCase) DeleteProduct(id string) error {
 	if err := uc.productRepo.Delete(id); err != nil {
 		return err
 	}
+	uc.suggestIndex.Remove(id)
 
 	// Invalidate cache
 	ctx := context.Background()
@@ -82,6 +122,48 @@ func (uc *ProductUseCase) DeleteProduct(id string) error {
 	return nil
 }
 
-func (uc *ProductUseCase) ListProducts(filter entity.ProductFilter) ([]entity.Product, error) {
+func (uc *ProductUseCase) ListProducts(filter entity.ProductFilter) (*entity.ProductPage, error) {
 	return uc.productRepo.FindAll(filter)
 }
+
+// SearchProducts runs a full-text search over product names, descriptions
+// and categories.
+func (uc *ProductUseCase) SearchProducts(search entity.ProductSearch) (*entity.SearchResult, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return nil, entity.ErrEmptySearchQuery
+	}
+	return uc.productRepo.Search(search)
+}
+
+// SuggestProducts completes a prefix typed into the search box with product
+// names and categories, most popular first.
+func (uc *ProductUseCase) SuggestProducts(prefix string, limit int) (entity.Suggestions, error) {
+	if strings.TrimSpace(prefix) == "" {
+		return entity.Suggestions{}, entity.ErrEmptySuggestPrefix
+	}
+	return uc.suggestIndex.Suggest(prefix, entity.SuggestLimit(limit)), nil
+}
+
+// BuildSuggestIndex loads every product into the suggestion index in one
+// pass. Units sold should be loaded first, so the index is ranked once.
+func (uc *ProductUseCase) BuildSuggestIndex() error {
+	filter := entity.ProductFilter{Limit: paging.MaxSize}
+	var products []entity.Product
+	for {
+		page, err := uc.productRepo.FindAll(filter)
+		if err != nil {
+			return err
+		}
+		products = append(products, page.Products...)
+
+		if page.NextPageToken == "" {
+			break
+		}
+		filter.PageToken = page.NextPageToken
+	}
+	uc.suggestIndex.Load(products)
+
+	log.Printf("Indexed %d products for suggestions", len(products))
+	return nil
+}
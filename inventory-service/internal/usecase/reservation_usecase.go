@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"inventory-service/internal/entity"
+	"inventory-service/internal/repository"
+)
+
+type ReservationUseCase struct {
+	reservationRepo repository.ReservationRepository
+	cacheRepo       repository.ProductCacheRepository
+	suggestIndex    repository.SuggestIndex
+	defaultTTL      time.Duration
+}
+
+func NewReservationUseCase(
+	reservationRepo repository.ReservationRepository,
+	cacheRepo repository.ProductCacheRepository,
+	suggestIndex repository.SuggestIndex,
+	defaultTTL time.Duration,
+) *ReservationUseCase {
+	return &ReservationUseCase{
+		reservationRepo: reservationRepo,
+		cacheRepo:       cacheRepo,
+		suggestIndex:    suggestIndex,
+		defaultTTL:      defaultTTL,
+	}
+}
+
+// LoadUnitsSold records the units sold so far in the suggestion index, which
+// ranks products by them. Call it before the products are loaded, so they
+// are ranked once. Commits and returns keep the ranking current afterwards.
+func (uc *ReservationUseCase) LoadUnitsSold() error {
+	sold, err := uc.reservationRepo.UnitsSold()
+	if err != nil {
+		return err
+	}
+	for productID, quantity := range sold {
+		uc.suggestIndex.AddUnitsSold(productID, int(quantity))
+	}
+	return nil
+}
+
+// ReserveStock takes quantity off the product and records a reservation for
+// it. The stock comes back automatically if the reservation is not committed
+// within ttl (or the configured default when ttl is zero). An order that
+// already holds the same quantity of the product gets its existing
+// reservation back, so retrying a reservation never takes the stock twice;
+// asking for a different quantity fails with ErrReservationExists.
+func (uc *ReservationUseCase) ReserveStock(productID, orderID string, quantity int, ttl time.Duration) (*entity.Reservation, error) {
+	if quantity <= 0 {
+		return nil, entity.ErrInvalidQuantity
+	}
+	if ttl <= 0 {
+		ttl = uc.defaultTTL
+	}
+
+	if orderID != "" {
+		existing, err := uc.reservationRepo.FindHeld(orderID, productID)
+		if err == nil {
+			return heldReservation(existing, quantity)
+		}
+		if !errors.Is(err, entity.ErrReservationNotFound) {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	reservation := &entity.Reservation{
+		ProductID: productID,
+		OrderID:   orderID,
+		Quantity:  quantity,
+		Status:    entity.ReservationStatusActive,
+		ExpiresAt: now.Add(ttl).Unix(),
+		CreatedAt: now.Unix(),
+		UpdatedAt: now.Unix(),
+	}
+	if err := uc.reservationRepo.Reserve(reservation); err != nil {
+		// A concurrent retry for the same order won the race
+		if errors.Is(err, entity.ErrReservationExists) {
+			existing, err := uc.reservationRepo.FindHeld(orderID, productID)
+			if err != nil {
+				return nil, err
+			}
+			return heldReservation(existing, quantity)
+		}
+		return nil, err
+	}
+	uc.invalidate(productID)
+
+	return reservation, nil
+}
+
+// heldReservation answers a retry with the reservation the order already
+// holds, provided it is for the quantity asked for.
+func heldReservation(existing *entity.Reservation, quantity int) (*entity.Reservation, error) {
+	if existing.Quantity != quantity {
+		return nil, fmt.Errorf("%w: holding %d, asked for %d", entity.ErrReservationExists, existing.Quantity, quantity)
+	}
+	return existing, nil
+}
+
+// CommitReservation makes a reservation's stock decrement permanent.
+// Committing an already committed reservation is a no-op.
+func (uc *ReservationUseCase) CommitReservation(id string) error {
+	reservation, err := uc.reservationRepo.Transition(id, entity.ReservationStatusActive, entity.ReservationStatusCommitted, time.Now().Unix())
+	if errors.Is(err, entity.ErrReservationNotActive) {
+		existing, findErr := uc.reservationRepo.FindByID(id)
+		if findErr == nil && existing.Status == entity.ReservationStatusCommitted {
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	uc.suggestIndex.AddUnitsSold(reservation.ProductID, reservation.Quantity)
+	return nil
+}
+
+// ReleaseStock cancels a reservation and returns its quantity to the product.
+// Releasing a reservation that was already released or has expired is a no-op.
+func (uc *ReservationUseCase) ReleaseStock(id string) error {
+	reservation, err := uc.reservationRepo.Settle(id, entity.ReservationStatusActive, entity.ReservationStatusReleased)
+	if err != nil {
+		if errors.Is(err, entity.ErrReservationNotActive) {
+			existing, findErr := uc.reservationRepo.FindByID(id)
+			if findErr == nil && (existing.Status == entity.ReservationStatusReleased || existing.Status == entity.ReservationStatusExpired) {
+				return nil
+			}
+		}
+		return err
+	}
+
+	uc.invalidate(reservation.ProductID)
+	return nil
+}
+
+// ReturnStock puts the quantity of a committed reservation back on the
+// product. Returning stock that was already returned is a no-op.
+func (uc *ReservationUseCase) ReturnStock(id string) error {
+	reservation, err := uc.reservationRepo.Settle(id, entity.ReservationStatusCommitted, entity.ReservationStatusReturned)
+	if err != nil {
+		if errors.Is(err, entity.ErrReservationNotActive) {
+			existing, findErr := uc.reservationRepo.FindByID(id)
+			if findErr == nil && existing.Status == entity.ReservationStatusReturned {
+				return nil
+			}
+		}
+		return err
+	}
+
+	uc.suggestIndex.AddUnitsSold(reservation.ProductID, -reservation.Quantity)
+	uc.invalidate(reservation.ProductID)
+	return nil
+}
+
+// ReleaseExpired returns the stock of every active reservation whose TTL has
+// passed and reports how many were released.
+func (uc *ReservationUseCase) ReleaseExpired() (int, error) {
+	expired, err := uc.reservationRepo.FindExpired(time.Now().Unix(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, r := range expired {
+		// The reservation only stops being active once its stock is back,
+		// so a failure here is retried on the next sweep
+		reservation, err := uc.reservationRepo.Settle(r.ID, entity.ReservationStatusActive, entity.ReservationStatusExpired)
+		if err != nil {
+			// Someone else committed or released it first.
+			if !errors.Is(err, entity.ErrReservationNotActive) {
+				log.Printf("Failed to expire reservation %s: %v", r.ID, err)
+			}
+			continue
+		}
+		uc.invalidate(reservation.ProductID)
+		released++
+	}
+	return released, nil
+}
+
+// StartExpiryWorker releases expired reservations every interval until ctx is
+// cancelled.
+func (uc *ReservationUseCase) StartExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := uc.ReleaseExpired()
+			if err != nil {
+				log.Printf("Failed to release expired reservations: %v", err)
+			} else if n > 0 {
+				log.Printf("Released %d expired reservations", n)
+			}
+		}
+	}
+}
+
+func (uc *ReservationUseCase) invalidate(productID string) {
+	if err := uc.cacheRepo.DeleteProduct(context.Background(), productID); err != nil {
+		log.Printf("Failed to invalidate cache for product %s: %v", productID, err)
+	}
+}
@@ -5,13 +5,18 @@ import (
 	"log"
 	"net"
 
+	"github.com/go-redis/redis/v8"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"authz"
 
 	"order-service/internal/config"
 	"order-service/internal/controller"
 	"order-service/internal/repository"
 	"order-service/internal/usecase"
 	pb "order-service/proto"
+	pbinv "order-service/proto/inventory"
 )
 
 func main() {
@@ -34,14 +39,66 @@ func main() {
 
 	// Initialize repository with both db and client for transactions
 	orderRepo := repository.NewOrderRepository(db, client)
+	if err := repository.EnsureOrderIndexes(db); err != nil {
+		log.Fatalf("Error creating order indexes: %v", err)
+	}
+	if err := repository.EnsureIdempotencyIndexes(db); err != nil {
+		log.Fatalf("Error creating idempotency key indexes: %v", err)
+	}
+	if migrated, err := repository.MigrateCompletedOrders(db); err != nil {
+		log.Fatalf("Error migrating completed orders: %v", err)
+	} else if migrated > 0 {
+		log.Printf("Migrated %d orders from the legacy completed status to paid", migrated)
+	}
+
+	// Verify the tokens callers send, and sign this service's own calls
+	verifier, err := authz.NewVerifier(authz.VerifierConfig{
+		Algorithm:     cfg.JWTAlgorithm,
+		Secret:        cfg.JWTSecret,
+		PublicKeyPath: cfg.JWTPublicKeyPath,
+		Issuer:        cfg.JWTIssuer,
+		ServiceSecret: cfg.ServiceTokenSecret,
+	})
+	if err != nil {
+		log.Fatalf("Error initializing token verifier: %v", err)
+	}
+	serviceCreds, err := authz.NewServiceCredentials("order-service", cfg.ServiceTokenSecret)
+	if err != nil {
+		log.Fatalf("Error initializing service credentials: %v", err)
+	}
+
+	// Connect to Inventory Service, which prices order items
+	inventoryConn, err := grpc.Dial(cfg.InventoryServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(serviceCreds),
+	)
+	if err != nil {
+		log.Fatalf("Error connecting to inventory service: %v", err)
+	}
+	defer inventoryConn.Close()
+	catalog := repository.NewProductCatalog(pbinv.NewInventoryServiceClient(inventoryConn))
+
+	// Connect to Redis, where carts are kept
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	defer redisClient.Close()
+	if _, err := redisClient.Ping(context.Background()).Result(); err != nil {
+		log.Fatalf("Error connecting to Redis: %v", err)
+	}
+	cartRepo := repository.NewCartRepository(redisClient)
 
-	// Initialize use case
-	orderUseCase := usecase.NewOrderUseCase(orderRepo)
+	// Initialize use cases
+	orderUseCase := usecase.NewOrderUseCase(orderRepo, catalog, cfg.IdempotencyKeyTTL)
+	cartUseCase := usecase.NewCartUseCase(cartRepo, catalog, orderUseCase, cfg.CartTTL)
 
 	// Initialize gRPC server
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(authz.UnaryServerInterceptor(verifier)))
 	orderController := controller.NewOrderController(orderUseCase)
 	pb.RegisterOrderServiceServer(grpcServer, orderController)
+	pb.RegisterCartServiceServer(grpcServer, controller.NewCartController(cartUseCase))
 
 	// Start gRPC server
 	listener, err := net.Listen("tcp", ":"+cfg.ServerPort)
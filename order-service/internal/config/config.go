@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -13,6 +14,28 @@ type Config struct {
 	MongoDBURI  string
 	MongoDBName string
 	ServerPort  string
+
+	InventoryServiceAddr string
+
+	// JWTAlgorithm, JWTSecret, JWTPublicKeyPath and JWTIssuer verify the
+	// access tokens the gateway forwards. ServiceTokenSecret verifies the
+	// service tokens of consumer-service and signs the calls this service
+	// makes to inventory-service.
+	JWTAlgorithm       string
+	JWTSecret          string
+	JWTPublicKeyPath   string
+	JWTIssuer          string
+	ServiceTokenSecret string
+
+	// IdempotencyKeyTTL is how long an Idempotency-Key keeps returning the
+	// order it created.
+	IdempotencyKeyTTL time.Duration
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	// CartTTL is how long a cart is kept after it was last changed.
+	CartTTL time.Duration
 }
 
 func NewConfig() *Config {
@@ -20,7 +43,29 @@ func NewConfig() *Config {
 		MongoDBURI:  "mongodb://localhost:27017",
 		MongoDBName: "order_db",
 		ServerPort:  "8081",
+
+		InventoryServiceAddr: "localhost:8080",
+
+		JWTAlgorithm:       getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSecret:          getEnv("JWT_SECRET", ""),
+		JWTPublicKeyPath:   getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		JWTIssuer:          getEnv("JWT_ISSUER", "user-service"),
+		ServiceTokenSecret: getEnv("SERVICE_TOKEN_SECRET", ""),
+
+		IdempotencyKeyTTL: 24 * time.Hour,
+
+		RedisAddr:     "localhost:6379",
+		RedisPassword: "",
+		RedisDB:       0,
+		CartTTL:       7 * 24 * time.Hour,
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
 	}
+	return defaultValue
 }
 
 // ConnectMongoDB returns a reference to the database (used when client isn't needed)
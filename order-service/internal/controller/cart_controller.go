@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"authz"
+	"order-service/internal/entity"
+	"order-service/internal/usecase"
+	pb "order-service/proto"
+)
+
+// guestCartID matches the cart IDs the gateway hands out to guests.
+var guestCartID = regexp.MustCompile(`^[A-Za-z0-9_-]{16,64}$`)
+
+type CartController struct {
+	pb.UnimplementedCartServiceServer
+	cartUseCase usecase.CartUseCase
+}
+
+func NewCartController(cartUseCase usecase.CartUseCase) *CartController {
+	return &CartController{
+		cartUseCase: cartUseCase,
+	}
+}
+
+func (c *CartController) GetCart(ctx context.Context, req *pb.GetCartRequest) (*pb.CartResponse, error) {
+	id, err := cartID(ctx, req.GetCartId())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := c.cartUseCase.GetCart(id)
+	if err != nil {
+		return nil, cartError(err, "failed to get cart")
+	}
+	return convertCartToResponse(cart), nil
+}
+
+func (c *CartController) AddCartItem(ctx context.Context, req *pb.AddCartItemRequest) (*pb.CartResponse, error) {
+	id, err := cartID(ctx, req.GetCartId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetProductId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "product id is required")
+	}
+
+	cart, err := c.cartUseCase.AddItem(id, req.GetProductId(), int(req.GetQuantity()))
+	if err != nil {
+		return nil, cartError(err, "failed to add cart item")
+	}
+	return convertCartToResponse(cart), nil
+}
+
+func (c *CartController) UpdateCartItem(ctx context.Context, req *pb.UpdateCartItemRequest) (*pb.CartResponse, error) {
+	id, err := cartID(ctx, req.GetCartId())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := c.cartUseCase.UpdateItem(id, req.GetProductId(), int(req.GetQuantity()))
+	if err != nil {
+		return nil, cartError(err, "failed to update cart item")
+	}
+	return convertCartToResponse(cart), nil
+}
+
+func (c *CartController) RemoveCartItem(ctx context.Context, req *pb.RemoveCartItemRequest) (*pb.CartResponse, error) {
+	id, err := cartID(ctx, req.GetCartId())
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := c.cartUseCase.RemoveItem(id, req.GetProductId())
+	if err != nil {
+		return nil, cartError(err, "failed to remove cart item")
+	}
+	return convertCartToResponse(cart), nil
+}
+
+func (c *CartController) MergeCart(ctx context.Context, req *pb.MergeCartRequest) (*pb.CartResponse, error) {
+	caller, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !guestCartID.MatchString(req.GetGuestCartId()) {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", entity.ErrInvalidCartID)
+	}
+
+	cart, err := c.cartUseCase.MergeCarts("guest:"+req.GetGuestCartId(), "user:"+caller.UserID)
+	if err != nil {
+		return nil, cartError(err, "failed to merge carts")
+	}
+	return convertCartToResponse(cart), nil
+}
+
+func (c *CartController) Checkout(ctx context.Context, req *pb.CheckoutRequest) (*pb.OrderResponse, error) {
+	caller, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := c.cartUseCase.Checkout("user:"+caller.UserID, caller.UserID, actorFor(caller))
+	if err != nil {
+		if errors.Is(err, entity.ErrTotalMismatch) {
+			return nil, quoteError(order, err)
+		}
+		return nil, cartError(err, "failed to check out")
+	}
+	return convertOrderToResponse(order), nil
+}
+
+// cartID resolves the cart a call works on: the caller's own cart when they
+// are logged in, otherwise the guest cart named in the request.
+func cartID(ctx context.Context, guestID string) (string, error) {
+	if caller, ok := authz.FromContext(ctx); ok && caller.UserID != "" {
+		return "user:" + caller.UserID, nil
+	}
+	if !guestCartID.MatchString(guestID) {
+		return "", status.Errorf(codes.InvalidArgument, "%v", entity.ErrInvalidCartID)
+	}
+	return "guest:" + guestID, nil
+}
+
+func cartError(err error, msg string) error {
+	switch {
+	case errors.Is(err, entity.ErrInvalidQuantity):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, entity.ErrProductNotFound), errors.Is(err, entity.ErrCartItemNotFound):
+		return status.Errorf(codes.NotFound, "%v", err)
+	case errors.Is(err, entity.ErrCartEmpty), errors.Is(err, entity.ErrCartItemUnavailable):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	case errors.Is(err, entity.ErrCatalogUnavailable):
+		return status.Errorf(codes.Unavailable, "%v", err)
+	case errors.Is(err, entity.ErrCartConflict):
+		return status.Errorf(codes.Aborted, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+func convertCartToResponse(cart *entity.Cart) *pb.CartResponse {
+	res := &pb.CartResponse{
+		Subtotal:      cart.Subtotal(),
+		Total:         cart.Subtotal(),
+		PricesChanged: cart.PricesChanged(),
+		UpdatedAt:     cart.UpdatedAt,
+		ExpiresAt:     cart.ExpiresAt,
+	}
+	// Only guests need their cart ID; a user's cart is found by who they are
+	if strings.HasPrefix(cart.ID, "guest:") {
+		res.CartId = strings.TrimPrefix(cart.ID, "guest:")
+	}
+	for _, item := range cart.Items {
+		res.Items = append(res.Items, &pb.CartItem{
+			ProductId:     item.ProductID,
+			Quantity:      int32(item.Quantity),
+			Price:         item.Price,
+			Name:          item.Name,
+			Category:      item.Category,
+			Subtotal:      item.Subtotal(),
+			PreviousPrice: item.PreviousPrice,
+			Available:     !item.Unavailable,
+		})
+	}
+	return res
+}
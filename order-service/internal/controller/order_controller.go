@@ -5,13 +5,20 @@ import (
 	"errors"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"authz"
 	"order-service/internal/entity"
 	"order-service/internal/usecase"
 	pb "order-service/proto"
+	"paging"
 )
 
+// idempotencyKeyMetadata is the gRPC metadata key the gateway forwards the
+// Idempotency-Key header in.
+const idempotencyKeyMetadata = "idempotency-key"
+
 type OrderController struct {
 	pb.UnimplementedOrderServiceServer
 	orderUseCase usecase.OrderUseCase
@@ -24,8 +31,19 @@ func NewOrderController(orderUseCase usecase.OrderUseCase) *OrderController {
 }
 
 func (c *OrderController) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.OrderResponse, error) {
+	caller, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customers always order for themselves; admins may order on behalf of a user.
+	userID := caller.UserID
+	if caller.IsAdmin() && req.GetUserId() != "" {
+		userID = req.GetUserId()
+	}
+
 	order := &entity.Order{
-		UserID: req.GetUserId(),
+		UserID: userID,
 		Total:  req.GetTotal(),
 		Status: entity.OrderStatusPending,
 	}
@@ -38,20 +56,59 @@ func (c *OrderController) CreateOrder(ctx context.Context, req *pb.CreateOrderRe
 		})
 	}
 
-	if err := c.orderUseCase.CreateOrder(order); err != nil {
+	if err := c.orderUseCase.CreateOrder(order, actorFor(caller), idempotencyKey(ctx)); err != nil {
+		switch {
+		case errors.Is(err, entity.ErrEmptyOrder), errors.Is(err, entity.ErrInvalidQuantity),
+			errors.Is(err, entity.ErrProductNotFound), errors.Is(err, entity.ErrInvalidIdempotencyKey):
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		case errors.Is(err, entity.ErrIdempotencyKeyReused):
+			return nil, status.Errorf(codes.AlreadyExists, "%v", err)
+		case errors.Is(err, entity.ErrDuplicateIdempotencyKey):
+			// The request holding the key is not visible yet; a retry
+			// gets its order
+			return nil, status.Errorf(codes.Aborted, "a request with the same idempotency key is in progress, retry")
+		case errors.Is(err, entity.ErrTotalMismatch):
+			return nil, quoteError(order, err)
+		case errors.Is(err, entity.ErrCatalogUnavailable):
+			return nil, status.Errorf(codes.Unavailable, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create order: %v", err)
 	}
 
 	return convertOrderToResponse(order), nil
 }
 
+// idempotencyKey returns the Idempotency-Key sent with the call, if any.
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// quoteError reports a total mismatch together with the pricing computed for
+// the order, so the client can show the current prices and retry.
+func quoteError(order *entity.Order, err error) error {
+	st := status.New(codes.FailedPrecondition, err.Error())
+	withQuote, detailErr := st.WithDetails(&pb.Quote{
+		Items:    convertItems(order.Items),
+		Subtotal: order.Subtotal,
+		Total:    order.Total,
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withQuote.Err()
+}
+
 func (c *OrderController) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.OrderResponse, error) {
-	order, err := c.orderUseCase.GetOrder(req.GetId())
+	order, err := c.ownedOrder(ctx, req.GetId())
 	if err != nil {
-		if errors.Is(err, entity.ErrOrderNotFound) {
-			return nil, status.Errorf(codes.NotFound, "order not found")
-		}
-		return nil, status.Errorf(codes.Internal, "failed to get order: %v", err)
+		return nil, err
 	}
 
 	return convertOrderToResponse(order), nil
@@ -63,10 +120,18 @@ func (c *OrderController) UpdateOrderStatus(ctx context.Context, req *pb.UpdateO
 		return nil, status.Errorf(codes.InvalidArgument, "invalid order status")
 	}
 
-	if err := c.orderUseCase.UpdateOrderStatus(req.GetId(), orderStatus); err != nil {
+	if _, err := c.ownedOrder(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	caller, _ := authz.FromContext(ctx)
+
+	if err := c.orderUseCase.UpdateOrderStatus(req.GetId(), orderStatus, req.GetReason(), actorFor(caller)); err != nil {
 		if errors.Is(err, entity.ErrOrderNotFound) {
 			return nil, status.Errorf(codes.NotFound, "order not found")
 		}
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update order status: %v", err)
 	}
 
@@ -79,44 +144,160 @@ func (c *OrderController) UpdateOrderStatus(ctx context.Context, req *pb.UpdateO
 	return convertOrderToResponse(order), nil
 }
 
+func (c *OrderController) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.OrderResponse, error) {
+	if _, err := c.ownedOrder(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	caller, _ := authz.FromContext(ctx)
+
+	if err := c.orderUseCase.CancelOrder(req.GetId(), req.GetReason(), actorFor(caller)); err != nil {
+		if errors.Is(err, entity.ErrOrderNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		if errors.Is(err, entity.ErrInvalidTransition) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to cancel order: %v", err)
+	}
+
+	order, err := c.orderUseCase.GetOrder(req.GetId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to fetch cancelled order: %v", err)
+	}
+
+	return convertOrderToResponse(order), nil
+}
+
 func (c *OrderController) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	caller, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customers only ever see their own orders, whatever user_id they ask for.
+	userID := req.GetUserId()
+	if !canSeeAllOrders(caller) {
+		userID = caller.UserID
+	}
+
 	filter := entity.OrderFilter{
-		UserID: req.GetUserId(),
-		Status: entity.OrderStatus(req.GetStatus()),
-		Page:   int(req.GetPage()),
-		Limit:  int(req.GetLimit()),
+		UserID:        userID,
+		Status:        entity.OrderStatus(req.GetStatus()),
+		Limit:         int(req.GetLimit()),
+		CreatedAfter:  req.GetCreatedAfter(),
+		CreatedBefore: req.GetCreatedBefore(),
+		MinTotal:      req.GetMinTotal(),
+		MaxTotal:      req.GetMaxTotal(),
+		ProductID:     req.GetProductId(),
+		OrderBy:       req.GetOrderBy(),
+		PageToken:     req.GetPageToken(),
+		IncludeTotal:  req.GetIncludeTotalCount(),
 	}
 
-	orders, err := c.orderUseCase.ListOrders(filter)
+	page, err := c.orderUseCase.ListOrders(filter)
 	if err != nil {
+		if errors.Is(err, paging.ErrInvalidToken) || errors.Is(err, paging.ErrInvalidOrderBy) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to list orders: %v", err)
 	}
 
 	var responses []*pb.OrderResponse
-	for _, order := range orders {
+	for _, order := range page.Orders {
 		responses = append(responses, convertOrderToResponse(&order))
 	}
 
-	return &pb.ListOrdersResponse{Orders: responses}, nil
+	return &pb.ListOrdersResponse{
+		Orders:        responses,
+		NextPageToken: page.NextPageToken,
+		TotalCount:    page.TotalCount,
+	}, nil
 }
 
-func convertOrderToResponse(order *entity.Order) *pb.OrderResponse {
+// ownedOrder loads an order the caller is allowed to act on. Orders owned by
+// someone else are reported as not found so their IDs cannot be probed.
+func (c *OrderController) ownedOrder(ctx context.Context, id string) (*entity.Order, error) {
+	caller, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := c.orderUseCase.GetOrder(id)
+	if err != nil {
+		if errors.Is(err, entity.ErrOrderNotFound) {
+			return nil, status.Errorf(codes.NotFound, "order not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get order: %v", err)
+	}
+
+	if !canSeeAllOrders(caller) && order.UserID != caller.UserID {
+		return nil, status.Errorf(codes.NotFound, "order not found")
+	}
+	return order, nil
+}
+
+func callerIdentity(ctx context.Context) (authz.Identity, error) {
+	caller, ok := authz.FromContext(ctx)
+	if !ok {
+		return authz.Identity{}, status.Errorf(codes.Unauthenticated, "caller identity required")
+	}
+	return caller, nil
+}
+
+// actorFor records who made a change: internal services by name, everyone
+// else as the user they are logged in as.
+func actorFor(caller authz.Identity) entity.Actor {
+	if caller.Role == authz.RoleService {
+		return entity.Actor{Type: entity.ActorTypeService, ID: caller.UserID}
+	}
+	return entity.Actor{Type: entity.ActorTypeUser, ID: caller.UserID}
+}
+
+// canSeeAllOrders is true for admins and for internal services such as
+// producer-service and consumer-service.
+func canSeeAllOrders(caller authz.Identity) bool {
+	return caller.IsAdmin() || caller.Role == authz.RoleService
+}
+
+func convertItems(orderItems []entity.OrderItem) []*pb.OrderItem {
 	var items []*pb.OrderItem
-	for _, item := range order.Items {
+	for _, item := range orderItems {
 		items = append(items, &pb.OrderItem{
 			ProductId: item.ProductID,
 			Quantity:  int32(item.Quantity),
 			Price:     item.Price,
+			Name:      item.Name,
+			Category:  item.Category,
+			Subtotal:  item.Subtotal,
+		})
+	}
+	return items
+}
+
+func convertOrderToResponse(order *entity.Order) *pb.OrderResponse {
+
+	var history []*pb.StatusChange
+	for _, change := range order.StatusHistory {
+		history = append(history, &pb.StatusChange{
+			FromStatus: string(change.From),
+			ToStatus:   string(change.To),
+			ChangedAt:  change.ChangedAt,
+			ActorType:  string(change.Actor.Type),
+			ActorId:    change.Actor.ID,
+			Reason:     change.Reason,
 		})
 	}
 
 	return &pb.OrderResponse{
-		Id:        order.ID,
-		UserId:    order.UserID,
-		Items:     items,
-		Total:     order.Total,
-		Status:    string(order.Status),
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		Id:            order.ID,
+		UserId:        order.UserID,
+		Items:         convertItems(order.Items),
+		Subtotal:      order.Subtotal,
+		Total:         order.Total,
+		Status:        string(order.Status),
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+		StatusReason:  order.StatusReason,
+		StatusHistory: history,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"errors"
+	"math"
+)
+
+// Cart is a shopping cart kept in Redis until it is checked out. Its ID is
+// "user:<user id>" for a logged-in user and "guest:<cart id>" for a guest.
+type Cart struct {
+	ID    string     `json:"id"`
+	Items []CartItem `json:"items"`
+	// Version goes up on every change, so it identifies the contents.
+	Version int64 `json:"version"`
+	// CheckedOutVersion is the last version an order was placed for, so a
+	// repeated checkout does not take the ordered items out twice.
+	CheckedOutVersion int64 `json:"checked_out_version,omitempty"`
+	UpdatedAt         int64 `json:"updated_at"`
+	ExpiresAt         int64 `json:"expires_at"`
+}
+
+// CartItem holds the catalog data of a product as of the last time the cart
+// was read, so a price change can be pointed out to the shopper.
+type CartItem struct {
+	ProductID string  `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+	Name      string  `json:"name"`
+	Category  string  `json:"category"`
+	// PreviousPrice is set on the read that noticed a price change.
+	PreviousPrice float64 `json:"-"`
+	// Unavailable is set once the product is no longer in the catalog.
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+// Subtotal is the item's price times its quantity, rounded to cents.
+func (i CartItem) Subtotal() float64 {
+	return math.Round(i.Price*float64(i.Quantity)*100) / 100
+}
+
+// Item returns the item for productID, or nil if the cart has none.
+func (c *Cart) Item(productID string) *CartItem {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			return &c.Items[i]
+		}
+	}
+	return nil
+}
+
+// RemoveItem drops the item for productID and reports whether there was one.
+func (c *Cart) RemoveItem(productID string) bool {
+	for i := range c.Items {
+		if c.Items[i].ProductID == productID {
+			c.Items = append(c.Items[:i], c.Items[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Subtotal sums the subtotals of the available items.
+func (c *Cart) Subtotal() float64 {
+	var subtotal float64
+	for _, item := range c.Items {
+		if !item.Unavailable {
+			subtotal += item.Subtotal()
+		}
+	}
+	return math.Round(subtotal*100) / 100
+}
+
+// PricesChanged reports whether the last read changed any item's price.
+func (c *Cart) PricesChanged() bool {
+	for _, item := range c.Items {
+		if item.PreviousPrice != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrCartEmpty           = errors.New("cart is empty")
+	ErrCartItemNotFound    = errors.New("product is not in the cart")
+	ErrCartItemUnavailable = errors.New("cart holds products that are no longer available")
+	ErrInvalidCartID       = errors.New("invalid cart id")
+	// ErrCartConflict means the cart kept changing while it was updated.
+	ErrCartConflict = errors.New("cart was changed concurrently, try again")
+)
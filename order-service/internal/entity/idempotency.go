@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"errors"
+	"time"
+)
+
+// IdempotencyRecord remembers the order created for an Idempotency-Key, so a
+// retried request gets the same order back instead of creating another one.
+// Keys are scoped to the user who sent them and expire at ExpiresAt.
+type IdempotencyRecord struct {
+	UserID string `bson:"user_id"`
+	Key    string `bson:"key"`
+	// RequestHash identifies the request the key was first used with.
+	RequestHash string    `bson:"request_hash"`
+	Order       Order     `bson:"order"`
+	CreatedAt   int64     `bson:"created_at"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+}
+
+var (
+	ErrIdempotencyKeyReused  = errors.New("idempotency key was already used with a different request")
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be 1 to 255 characters")
+	// ErrDuplicateIdempotencyKey means a concurrent request stored the key
+	// first; its order is the one to return. It is only returned while that
+	// order cannot be read yet, and the client should retry.
+	ErrDuplicateIdempotencyKey = errors.New("idempotency key already stored")
+)
@@ -6,43 +6,142 @@ type OrderStatus string
 
 const (
 	OrderStatusPending   OrderStatus = "pending"
-	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusReserved  OrderStatus = "reserved"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
 	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusFailed    OrderStatus = "failed"
+	OrderStatusRefunded  OrderStatus = "refunded"
 )
 
+// OrderStatusCompleted is the status placed orders were left in before the
+// lifecycle above existed. It is not a valid status any more:
+// MigrateCompletedOrders moves such orders on to OrderStatusPaid.
+const OrderStatusCompleted OrderStatus = "completed"
+
+// orderTransitions lists, for every status, the statuses an order may move to
+// next. Cancelled, failed and refunded orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusReserved, OrderStatusCancelled, OrderStatusFailed},
+	OrderStatusReserved:  {OrderStatusPaid, OrderStatusCancelled, OrderStatusFailed},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusShipped, OrderStatusRefunded},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {OrderStatusRefunded},
+	OrderStatusCancelled: {},
+	OrderStatusFailed:    {},
+	OrderStatusRefunded:  {},
+}
+
 func (os OrderStatus) IsValid() bool {
-	switch os {
-	case OrderStatusPending, OrderStatusCompleted, OrderStatusCancelled:
-		return true
-	default:
-		return false
+	_, ok := orderTransitions[os]
+	return ok
+}
+
+// CanTransitionTo reports whether an order in status os may move to next.
+func (os OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[os] {
+		if allowed == next {
+			return true
+		}
 	}
+	return false
+}
+
+type ActorType string
+
+const (
+	ActorTypeUser    ActorType = "user"
+	ActorTypeService ActorType = "service"
+	ActorTypeSystem  ActorType = "system"
+)
+
+// Actor is whoever caused a status change.
+type Actor struct {
+	Type ActorType `bson:"type"`
+	ID   string    `bson:"id,omitempty"`
+}
+
+// SystemActor is used for changes made by order-service itself.
+var SystemActor = Actor{Type: ActorTypeSystem}
+
+// StatusChange is one entry of an order's status history. From is empty for
+// the entry recorded when the order is created.
+type StatusChange struct {
+	From      OrderStatus `bson:"from"`
+	To        OrderStatus `bson:"to"`
+	ChangedAt int64       `bson:"changed_at"`
+	Actor     Actor       `bson:"actor"`
+	Reason    string      `bson:"reason,omitempty"`
 }
 
+// OrderItem snapshots the product as it was priced when the order was
+// placed, so later catalog changes do not alter the order.
 type OrderItem struct {
 	ProductID string  `bson:"product_id"`
 	Quantity  int     `bson:"quantity"`
 	Price     float64 `bson:"price"`
+	Name      string  `bson:"name,omitempty"`
+	Category  string  `bson:"category,omitempty"`
+	Subtotal  float64 `bson:"subtotal"`
 }
 
 type Order struct {
-	ID        string      `bson:"_id,omitempty"`
-	UserID    string      `bson:"user_id"`
-	Items     []OrderItem `bson:"items"`
-	Total     float64     `bson:"total"`
-	Status    OrderStatus `bson:"status"`
-	CreatedAt int64       `bson:"created_at"`
-	UpdatedAt int64       `bson:"updated_at"`
+	ID       string      `bson:"_id,omitempty"`
+	UserID   string      `bson:"user_id"`
+	Items    []OrderItem `bson:"items"`
+	Subtotal float64     `bson:"subtotal"`
+	Total    float64     `bson:"total"`
+	Status   OrderStatus `bson:"status"`
+	// StatusReason explains the most recent status change.
+	StatusReason  string         `bson:"status_reason,omitempty"`
+	StatusHistory []StatusChange `bson:"status_history"`
+	CreatedAt     int64          `bson:"created_at"`
+	UpdatedAt     int64          `bson:"updated_at"`
 }
 
 type OrderFilter struct {
     UserID      string
     Status      OrderStatus
-    Page        int
     Limit       int
     CreatedAfter int64  // Add this new field
+    // CreatedBefore is exclusive, so consecutive ranges do not overlap.
+    CreatedBefore int64
+    MinTotal      float64
+    MaxTotal      float64
+    // ProductID keeps only orders containing that product.
+    ProductID string
+    // OrderBy is one of OrderSortFields, optionally followed by "asc" or
+    // "desc".
+    OrderBy string
+    // PageToken continues a listing where the previous page ended.
+    PageToken    string
+    // IncludeTotal asks for the number of matching orders on all pages.
+    IncludeTotal bool
+}
+
+// OrderPage is one page of an order listing. NextPageToken is empty on the
+// last page and TotalCount is only set when it was asked for.
+type OrderPage struct {
+    Orders        []Order
+    NextPageToken string
+    TotalCount    *int64
+}
+
+// OrderSortFields maps the fields orders can be ordered by to their bson
+// fields. Order IDs are ObjectIDs, so sorting by ID sorts by creation time.
+var OrderSortFields = map[string]string{
+	"created_at": "_id",
+	"updated_at": "updated_at",
+	"total":      "total",
 }
 
 var (
-	ErrOrderNotFound = errors.New("order not found")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrEmptyOrder        = errors.New("order has no items")
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+	ErrTotalMismatch     = errors.New("order total does not match current prices")
 )
\ No newline at end of file
@@ -0,0 +1,15 @@
+package entity
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// OutboxEvent is a domain event written in the same transaction as the order
+// change that caused it. Payload holds the encoded event envelope, whose ID
+// is the hex form of ID. producer-service relays the events to NATS in _id
+// order, using Type as the subject, and deletes each one once it is sent.
+type OutboxEvent struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	AggregateID string             `bson:"aggregate_id"`
+	Type        string             `bson:"type"`
+	Payload     []byte             `bson:"payload"`
+	CreatedAt   int64              `bson:"created_at"`
+}
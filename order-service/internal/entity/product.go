@@ -0,0 +1,16 @@
+package entity
+
+import "errors"
+
+// Product is the catalog data order-service prices an order item with.
+type Product struct {
+	ID       string
+	Name     string
+	Category string
+	Price    float64
+}
+
+var (
+	ErrProductNotFound    = errors.New("product not found")
+	ErrCatalogUnavailable = errors.New("product catalog unavailable")
+)
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"order-service/internal/entity"
+)
+
+// CartRepository stores carts in Redis. Every save restarts the cart's TTL,
+// so carts that are left alone expire on their own.
+type CartRepository interface {
+	Get(id string) (*entity.Cart, error)
+	// Update applies fn to the cart stored under id, or to a new empty cart
+	// if there is none, and saves the result. A cart changed by someone else
+	// meanwhile is read again and fn runs again, so fn may be called more
+	// than once. An error from fn is returned without saving.
+	Update(id string, expiration time.Duration, fn func(cart *entity.Cart) error) (*entity.Cart, error)
+	Delete(id string) error
+}
+
+// maxCartUpdateAttempts bounds how often Update retries a cart that keeps
+// changing under it before giving up with ErrCartConflict.
+const maxCartUpdateAttempts = 5
+
+type cartRepository struct {
+	client *redis.Client
+}
+
+func NewCartRepository(client *redis.Client) CartRepository {
+	return &cartRepository{client: client}
+}
+
+// Get returns the cart stored under id, or nil if there is none.
+func (r *cartRepository) Get(id string) (*entity.Cart, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return getCart(ctx, r.client, "cart:"+id)
+}
+
+// Update WATCHes the cart while fn runs and writes it back in a MULTI, so the
+// write fails instead of overwriting a change made in between, for example
+// from a second browser tab.
+func (r *cartRepository) Update(id string, expiration time.Duration, fn func(cart *entity.Cart) error) (*entity.Cart, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	key := "cart:" + id
+	var cart *entity.Cart
+	update := func(tx *redis.Tx) error {
+		var err error
+		cart, err = getCart(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		if cart == nil {
+			cart = &entity.Cart{ID: id}
+		}
+		if err := fn(cart); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		cart.Version++
+		cart.UpdatedAt = now.Unix()
+		cart.ExpiresAt = now.Add(expiration).Unix()
+		data, err := json.Marshal(cart)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, expiration)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxCartUpdateAttempts; attempt++ {
+		err := r.client.Watch(ctx, update, key)
+		if err == nil {
+			return cart, nil
+		}
+		if err != redis.TxFailedErr {
+			return nil, err
+		}
+	}
+	return nil, entity.ErrCartConflict
+}
+
+func getCart(ctx context.Context, client redis.Cmdable, key string) (*entity.Cart, error) {
+	val, err := client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cart entity.Cart
+	if err := json.Unmarshal([]byte(val), &cart); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *cartRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.client.Del(ctx, "cart:"+id).Err()
+}
@@ -5,34 +5,173 @@ import (
 	"log"
 	"time"
 
+	"events"
+	eventspb "events/proto"
 	"order-service/internal/entity"
+	"paging"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/protobuf/proto"
 )
 
+// eventSource identifies this service as the source of the events it writes.
+const eventSource = "order-service"
+
 type OrderRepository interface {
-	Create(order *entity.Order) error
+	// Create stores the order. When idempotency is set it is stored with the
+	// order in the same transaction, and ErrDuplicateIdempotencyKey is
+	// returned without creating the order if the key is already taken.
+	Create(order *entity.Order, idempotency *entity.IdempotencyRecord) error
+	FindByIdempotencyKey(userID, key string) (*entity.IdempotencyRecord, error)
 	FindByID(id string) (*entity.Order, error)
-	UpdateStatus(id string, status entity.OrderStatus) error
-	FindAll(filter entity.OrderFilter) ([]entity.Order, error)
+	UpdateStatus(id string, change entity.StatusChange) error
+	FindAll(filter entity.OrderFilter) (*entity.OrderPage, error)
 }
 
 type orderRepository struct {
-	collection *mongo.Collection
-	client     *mongo.Client // For transaction support
+	collection      *mongo.Collection
+	outbox          *mongo.Collection
+	idempotencyKeys *mongo.Collection
+	client          *mongo.Client // For transaction support
 }
 
 func NewOrderRepository(db *mongo.Database, client *mongo.Client) OrderRepository {
 	return &orderRepository{
-		collection: db.Collection("orders"),
-		client:     client,
+		collection:      db.Collection("orders"),
+		outbox:          db.Collection("outbox"),
+		idempotencyKeys: db.Collection("idempotency_keys"),
+		client:          client,
+	}
+}
+
+// EnsureOrderIndexes creates the indexes behind order listings: one per sort
+// order, alone and after the user_id and status filters, each ending with
+// the _id tie-breaker.
+func EnsureOrderIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.Collection("orders").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "total", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "total", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "total", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "items.product_id", Value: 1}}},
+	})
+	return err
+}
+
+// EnsureIdempotencyIndexes makes idempotency keys unique per user and lets
+// MongoDB delete them once they expire.
+func EnsureIdempotencyIndexes(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.Collection("idempotency_keys").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+// MigrateCompletedOrders moves orders still in the legacy completed status to
+// paid, the first status of the current lifecycle where their stock is taken
+// for good and they can still be fulfilled or refunded. The move is recorded
+// in their status history as a change by the system. It returns the number
+// of orders moved and is a no-op once none are left.
+func MigrateCompletedOrders(db *mongo.Database) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	change := entity.StatusChange{
+		From:      entity.OrderStatusCompleted,
+		To:        entity.OrderStatusPaid,
+		ChangedAt: time.Now().Unix(),
+		Actor:     entity.SystemActor,
+		Reason:    "migrated from the legacy completed status",
+	}
+	res, err := db.Collection("orders").UpdateMany(ctx,
+		bson.M{"status": entity.OrderStatusCompleted},
+		bson.M{
+			"$set": bson.M{
+				"status":        change.To,
+				"status_reason": change.Reason,
+				"updated_at":    change.ChangedAt,
+			},
+			"$push": bson.M{"status_history": change},
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// appendOutbox stores an event for the relay as part of the caller's
+// transaction. All events of one order share the order ID as correlation ID.
+func (r *orderRepository) appendOutbox(sessCtx mongo.SessionContext, orderID, eventType string, payload proto.Message) error {
+	id := primitive.NewObjectID()
+	now := time.Now()
+
+	data, err := events.Encode(events.Meta{
+		ID:            id.Hex(),
+		Type:          eventType,
+		Source:        eventSource,
+		Subject:       orderID,
+		Time:          now,
+		CorrelationID: orderID,
+	}, payload)
+	if err != nil {
+		return err
 	}
+	_, err = r.outbox.InsertOne(sessCtx, entity.OutboxEvent{
+		ID:          id,
+		AggregateID: orderID,
+		Type:        eventType,
+		Payload:     data,
+		CreatedAt:   now.Unix(),
+	})
+	return err
 }
 
-func (r *orderRepository) Create(order *entity.Order) error {
+func orderCreatedEvent(order *entity.Order) *eventspb.OrderCreated {
+	event := &eventspb.OrderCreated{
+		OrderId:   order.ID,
+		UserId:    order.UserID,
+		Subtotal:  order.Subtotal,
+		Total:     order.Total,
+		Status:    string(order.Status),
+		CreatedAt: order.CreatedAt,
+		UpdatedAt: order.UpdatedAt,
+	}
+	for _, item := range order.Items {
+		event.Items = append(event.Items, &eventspb.OrderItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			Price:     item.Price,
+			Name:      item.Name,
+			Category:  item.Category,
+			Subtotal:  item.Subtotal,
+		})
+	}
+	return event
+}
+
+func (r *orderRepository) Create(order *entity.Order, idempotency *entity.IdempotencyRecord) error {
     log.Printf("Starting order creation for user %s", order.UserID)
 
     ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -52,18 +191,27 @@ func (r *orderRepository) Create(order *entity.Order) error {
         order.CreatedAt = time.Now().Unix()
         order.UpdatedAt = order.CreatedAt
 
-        if order.Total == 0 {
-            for _, item := range order.Items {
-                order.Total += item.Price * float64(item.Quantity)
-            }
-            log.Printf("Calculated order total: %.2f", order.Total)
-        }
-
-        _, err := r.collection.InsertOne(sessCtx, order)
+        // The callback may be retried, so let MongoDB assign a fresh ID each time
+        order.ID = ""
+        res, err := r.collection.InsertOne(sessCtx, order)
         if err != nil {
             log.Printf("Failed to insert order: %v", err)
             return nil, err
         }
+        if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+            order.ID = oid.Hex()
+        }
+
+        if err := r.appendOutbox(sessCtx, order.ID, events.TypeOrderCreated, orderCreatedEvent(order)); err != nil {
+            log.Printf("Failed to write outbox event: %v", err)
+            return nil, err
+        }
+
+        if idempotency != nil {
+            if err := r.storeIdempotencyKey(sessCtx, order, idempotency); err != nil {
+                return nil, err
+            }
+        }
 
         log.Println("Order successfully created in transaction")
         return nil, nil
@@ -78,48 +226,150 @@ func (r *orderRepository) Create(order *entity.Order) error {
     return err
 }
 
+// storeIdempotencyKey records the order created for a key. An expired record
+// the TTL monitor has not removed yet is replaced.
+func (r *orderRepository) storeIdempotencyKey(sessCtx mongo.SessionContext, order *entity.Order, idempotency *entity.IdempotencyRecord) error {
+	_, err := r.idempotencyKeys.DeleteOne(sessCtx, bson.M{
+		"user_id":    idempotency.UserID,
+		"key":        idempotency.Key,
+		"expires_at": bson.M{"$lte": time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+
+	idempotency.Order = *order
+	idempotency.CreatedAt = order.CreatedAt
+	_, err = r.idempotencyKeys.InsertOne(sessCtx, idempotency)
+	if mongo.IsDuplicateKeyError(err) {
+		return entity.ErrDuplicateIdempotencyKey
+	}
+	return err
+}
+
+// FindByIdempotencyKey returns the unexpired record for a user's key, or nil
+// if there is none.
+func (r *orderRepository) FindByIdempotencyKey(userID, key string) (*entity.IdempotencyRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var record entity.IdempotencyRecord
+	err := r.idempotencyKeys.FindOne(ctx, bson.M{
+		"user_id":    userID,
+		"key":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&record)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
 func (r *orderRepository) FindByID(id string) (*entity.Order, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, entity.ErrOrderNotFound
 	}
 
 	var order entity.Order
 	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&order)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, entity.ErrOrderNotFound
+		}
 		return nil, err
 	}
 
 	return &order, nil
 }
 
-func (r *orderRepository) UpdateStatus(id string, status entity.OrderStatus) error {
+// UpdateStatus moves the order to change.To only while it is still in
+// change.From, so two concurrent transitions cannot both succeed, appends
+// the change to the order's status history and writes an
+// order.status_changed outbox event in the same transaction. Cancelling an
+// order also writes order.cancelled, which tells consumers to give back its
+// stock.
+func (r *orderRepository) UpdateStatus(id string, change entity.StatusChange) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entity.ErrOrderNotFound
+	}
+
+	session, err := r.client.StartSession()
 	if err != nil {
 		return err
 	}
+	defer session.EndSession(ctx)
 
 	update := bson.M{
 		"$set": bson.M{
-			"status":     status,
-			"updated_at": time.Now().Unix(),
+			"status":        change.To,
+			"status_reason": change.Reason,
+			"updated_at":    change.ChangedAt,
 		},
+		"$push": bson.M{"status_history": change},
 	}
 
-	_, err = r.collection.UpdateByID(ctx, objectID, update)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		var order entity.Order
+		err := r.collection.FindOneAndUpdate(sessCtx, bson.M{"_id": objectID, "status": change.From}, update).Decode(&order)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, entity.ErrInvalidTransition
+			}
+			return nil, err
+		}
+
+		err = r.appendOutbox(sessCtx, id, events.TypeOrderStatusChanged, &eventspb.OrderStatusChanged{
+			OrderId:    id,
+			UserId:     order.UserID,
+			FromStatus: string(change.From),
+			ToStatus:   string(change.To),
+			Reason:     change.Reason,
+			ActorType:  string(change.Actor.Type),
+			ActorId:    change.Actor.ID,
+			ChangedAt:  change.ChangedAt,
+		})
+		if err != nil || change.To != entity.OrderStatusCancelled {
+			return nil, err
+		}
+
+		cancelled := &eventspb.OrderCancelled{
+			OrderId:     id,
+			UserId:      order.UserID,
+			FromStatus:  string(change.From),
+			Reason:      change.Reason,
+			ActorType:   string(change.Actor.Type),
+			ActorId:     change.Actor.ID,
+			CancelledAt: change.ChangedAt,
+			Items:       orderCreatedEvent(&order).Items,
+		}
+		return nil, r.appendOutbox(sessCtx, id, events.TypeOrderCancelled, cancelled)
+	})
 	return err
 }
 
-func (r *orderRepository) FindAll(filter entity.OrderFilter) ([]entity.Order, error) {
+// FindAll returns one page of the orders matching filter. Every ordering
+// ends with the ID, so that pages neither skip nor repeat orders when new
+// ones are placed.
+func (r *orderRepository) FindAll(filter entity.OrderFilter) (*entity.OrderPage, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	sort, err := paging.ParseSort(filter.OrderBy, entity.OrderSortFields)
+	if err != nil {
+		return nil, err
+	}
+
 	query := bson.M{}
 	if filter.UserID != "" {
 		query["user_id"] = filter.UserID
@@ -127,28 +377,78 @@ func (r *orderRepository) FindAll(filter entity.OrderFilter) ([]entity.Order, er
 	if filter.Status != "" {
 		query["status"] = filter.Status
 	}
-	if filter.CreatedAfter > 0 {
-		query["created_at"] = bson.M{"$gte": filter.CreatedAfter}
+	if filter.CreatedAfter > 0 || filter.CreatedBefore > 0 {
+		createdQuery := bson.M{}
+		if filter.CreatedAfter > 0 {
+			createdQuery["$gte"] = filter.CreatedAfter
+		}
+		if filter.CreatedBefore > 0 {
+			createdQuery["$lt"] = filter.CreatedBefore
+		}
+		query["created_at"] = createdQuery
+	}
+	if filter.MinTotal > 0 || filter.MaxTotal > 0 {
+		totalQuery := bson.M{}
+		if filter.MinTotal > 0 {
+			totalQuery["$gte"] = filter.MinTotal
+		}
+		if filter.MaxTotal > 0 {
+			totalQuery["$lte"] = filter.MaxTotal
+		}
+		query["total"] = totalQuery
+	}
+	if filter.ProductID != "" {
+		query["items.product_id"] = filter.ProductID
+	}
+
+	page := &entity.OrderPage{}
+	if filter.IncludeTotal {
+		total, err := r.collection.CountDocuments(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalCount = &total
 	}
 
-	opts := options.Find()
-	if filter.Limit > 0 {
-		opts.SetLimit(int64(filter.Limit))
-		if filter.Page > 0 {
-			opts.SetSkip(int64((filter.Page - 1) * filter.Limit))
+	pageQuery := query
+	if filter.PageToken != "" {
+		after, err := sort.After(filter.PageToken)
+		if err != nil {
+			return nil, err
 		}
+		pageQuery = bson.M{"$and": bson.A{query, after}}
 	}
 
-	cursor, err := r.collection.Find(ctx, query, opts)
+	// Fetch one extra order to learn whether another page follows
+	size := paging.Size(filter.Limit)
+	opts := options.Find().
+		SetSort(sort.Order()).
+		SetLimit(int64(size + 1))
+
+	cursor, err := r.collection.Find(ctx, pageQuery, opts)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var orders []entity.Order
-	if err = cursor.All(ctx, &orders); err != nil {
+	if err = cursor.All(ctx, &page.Orders); err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	if len(page.Orders) > size {
+		page.Orders = page.Orders[:size]
+		last := page.Orders[size-1]
+		page.NextPageToken = sort.NextToken(last.ID, orderSortValue(last, sort))
+	}
+	return page, nil
+}
+
+func orderSortValue(order entity.Order, sort paging.Sort) interface{} {
+	switch sort.Field {
+	case "total":
+		return order.Total
+	case "updated_at":
+		return order.UpdatedAt
+	}
+	return nil
 }
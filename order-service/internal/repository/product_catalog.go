@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"order-service/internal/entity"
+	pbinv "order-service/proto/inventory"
+)
+
+// ProductCatalog looks up the current catalog data of products.
+type ProductCatalog interface {
+	GetProduct(id string) (*entity.Product, error)
+}
+
+type inventoryCatalog struct {
+	client pbinv.InventoryServiceClient
+}
+
+// NewProductCatalog reads products from inventory-service. The client's
+// connection must sign calls with a service token.
+func NewProductCatalog(client pbinv.InventoryServiceClient) ProductCatalog {
+	return &inventoryCatalog{client: client}
+}
+
+func (c *inventoryCatalog) GetProduct(id string) (*entity.Product, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := c.client.GetProduct(ctx, &pbinv.GetProductRequest{Id: id})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("%w: %s", entity.ErrProductNotFound, id)
+		}
+		return nil, fmt.Errorf("%w: %v", entity.ErrCatalogUnavailable, err)
+	}
+
+	return &entity.Product{
+		ID:       res.GetId(),
+		Name:     res.GetName(),
+		Category: res.GetCategory(),
+		Price:    res.GetPrice(),
+	}, nil
+}
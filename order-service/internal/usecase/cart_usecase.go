@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"order-service/internal/entity"
+	"order-service/internal/repository"
+	"time"
+)
+
+type CartUseCase interface {
+	GetCart(id string) (*entity.Cart, error)
+	AddItem(id, productID string, quantity int) (*entity.Cart, error)
+	UpdateItem(id, productID string, quantity int) (*entity.Cart, error)
+	RemoveItem(id, productID string) (*entity.Cart, error)
+	MergeCarts(guestID, id string) (*entity.Cart, error)
+	Checkout(id, userID string, actor entity.Actor) (*entity.Order, error)
+}
+
+type cartUseCase struct {
+	cartRepo     repository.CartRepository
+	catalog      repository.ProductCatalog
+	orderUseCase OrderUseCase
+	ttl          time.Duration
+}
+
+func NewCartUseCase(cartRepo repository.CartRepository, catalog repository.ProductCatalog, orderUseCase OrderUseCase, ttl time.Duration) CartUseCase {
+	return &cartUseCase{
+		cartRepo:     cartRepo,
+		catalog:      catalog,
+		orderUseCase: orderUseCase,
+		ttl:          ttl,
+	}
+}
+
+// GetCart returns the cart with every item repriced from the catalog. A cart
+// that does not exist yet is returned empty.
+func (uc *cartUseCase) GetCart(id string) (*entity.Cart, error) {
+	cart, err := uc.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return cart, nil
+	}
+
+	changed, err := uc.revalidate(cart)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		// Store the new prices, so the change is pointed out only once and
+		// checkout holds the shopper to what they were last shown
+		return uc.update(id, func(*entity.Cart) error { return nil })
+	}
+	return cart, nil
+}
+
+// AddItem puts quantity more of a product in the cart.
+func (uc *cartUseCase) AddItem(id, productID string, quantity int) (*entity.Cart, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidQuantity, productID)
+	}
+
+	return uc.update(id, func(cart *entity.Cart) error {
+		if item := cart.Item(productID); item != nil {
+			item.Quantity += quantity
+			return nil
+		}
+
+		// Reject unknown products right away rather than on the next read
+		product, err := uc.catalog.GetProduct(productID)
+		if err != nil {
+			return err
+		}
+		cart.Items = append(cart.Items, entity.CartItem{
+			ProductID: product.ID,
+			Quantity:  quantity,
+			Price:     product.Price,
+			Name:      product.Name,
+			Category:  product.Category,
+		})
+		return nil
+	})
+}
+
+// UpdateItem sets the quantity of a product in the cart. A quantity of zero
+// removes it.
+func (uc *cartUseCase) UpdateItem(id, productID string, quantity int) (*entity.Cart, error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: %s", entity.ErrInvalidQuantity, productID)
+	}
+
+	return uc.update(id, func(cart *entity.Cart) error {
+		if quantity == 0 {
+			if !cart.RemoveItem(productID) {
+				return entity.ErrCartItemNotFound
+			}
+			return nil
+		}
+
+		item := cart.Item(productID)
+		if item == nil {
+			return entity.ErrCartItemNotFound
+		}
+		item.Quantity = quantity
+		return nil
+	})
+}
+
+func (uc *cartUseCase) RemoveItem(id, productID string) (*entity.Cart, error) {
+	return uc.update(id, func(cart *entity.Cart) error {
+		if !cart.RemoveItem(productID) {
+			return entity.ErrCartItemNotFound
+		}
+		return nil
+	})
+}
+
+// MergeCarts adds the items of a guest cart to the cart id and deletes the
+// guest cart. Quantities of products found in both carts are added up.
+// Merging a guest cart that is gone is a no-op.
+func (uc *cartUseCase) MergeCarts(guestID, id string) (*entity.Cart, error) {
+	guest, err := uc.cartRepo.Get(guestID)
+	if err != nil {
+		return nil, err
+	}
+	if guest == nil || len(guest.Items) == 0 {
+		return uc.GetCart(id)
+	}
+
+	cart, err := uc.update(id, func(cart *entity.Cart) error {
+		for _, guestItem := range guest.Items {
+			if item := cart.Item(guestItem.ProductID); item != nil {
+				item.Quantity += guestItem.Quantity
+				continue
+			}
+			cart.Items = append(cart.Items, guestItem)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.cartRepo.Delete(guestID); err != nil {
+		log.Printf("Failed to delete merged guest cart %s: %v", guestID, err)
+	}
+	return cart, nil
+}
+
+// Checkout places an order for the cart at the prices the shopper was last
+// shown and takes the ordered items out of the cart. When prices changed
+// since, the cart is updated to the current ones and ErrTotalMismatch is
+// returned together with the priced order, so the shopper can review the
+// change and check out again.
+//
+// The order is keyed by the cart version, so submitting the same cart twice
+// places one order. Items added while the order is placed stay in the cart.
+func (uc *cartUseCase) Checkout(id, userID string, actor entity.Actor) (*entity.Order, error) {
+	cart, err := uc.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, entity.ErrCartEmpty
+	}
+
+	order := &entity.Order{
+		UserID: userID,
+		Total:  cart.Subtotal(),
+		Status: entity.OrderStatusPending,
+	}
+	for _, item := range cart.Items {
+		if item.Unavailable {
+			return nil, fmt.Errorf("%w: %s", entity.ErrCartItemUnavailable, item.ProductID)
+		}
+		order.Items = append(order.Items, entity.OrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	err = uc.orderUseCase.CreateOrder(order, actor, checkoutKey(cart))
+	if err != nil {
+		if errors.Is(err, entity.ErrTotalMismatch) {
+			if _, revalidateErr := uc.GetCart(id); revalidateErr != nil {
+				log.Printf("Failed to reprice cart %s: %v", id, revalidateErr)
+			}
+			return order, err
+		}
+		if errors.Is(err, entity.ErrProductNotFound) {
+			// Mark the product unavailable for the next read
+			if _, revalidateErr := uc.GetCart(id); revalidateErr != nil {
+				log.Printf("Failed to reprice cart %s: %v", id, revalidateErr)
+			}
+			return nil, fmt.Errorf("%w: %v", entity.ErrCartItemUnavailable, err)
+		}
+		return nil, err
+	}
+
+	_, err = uc.update(id, func(current *entity.Cart) error {
+		if current.CheckedOutVersion >= cart.Version {
+			return nil
+		}
+		current.CheckedOutVersion = cart.Version
+		for _, ordered := range cart.Items {
+			item := current.Item(ordered.ProductID)
+			if item == nil {
+				continue
+			}
+			item.Quantity -= ordered.Quantity
+			if item.Quantity <= 0 {
+				current.RemoveItem(ordered.ProductID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to take the items of order %s out of cart %s: %v", order.ID, id, err)
+	}
+	return order, nil
+}
+
+// checkoutKey is the idempotency key of an order placed for the cart as it
+// is now.
+func checkoutKey(cart *entity.Cart) string {
+	return fmt.Sprintf("checkout:%s:%d", cart.ID, cart.Version)
+}
+
+// update applies fn to the cart, reprices it and saves it. Concurrent updates
+// of the same cart do not overwrite each other: fn runs again on the cart
+// the other update saved.
+func (uc *cartUseCase) update(id string, fn func(cart *entity.Cart) error) (*entity.Cart, error) {
+	return uc.cartRepo.Update(id, uc.ttl, func(cart *entity.Cart) error {
+		if err := fn(cart); err != nil {
+			return err
+		}
+		_, err := uc.revalidate(cart)
+		return err
+	})
+}
+
+func (uc *cartUseCase) load(id string) (*entity.Cart, error) {
+	cart, err := uc.cartRepo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart = &entity.Cart{ID: id}
+	}
+	return cart, nil
+}
+
+// revalidate refreshes the catalog data of every item and reports whether
+// anything changed. Items whose product is gone are marked unavailable
+// rather than dropped, so the shopper sees what happened to them.
+func (uc *cartUseCase) revalidate(cart *entity.Cart) (bool, error) {
+	changed := false
+	for i := range cart.Items {
+		item := &cart.Items[i]
+
+		product, err := uc.catalog.GetProduct(item.ProductID)
+		if err != nil {
+			if errors.Is(err, entity.ErrProductNotFound) {
+				changed = changed || !item.Unavailable
+				item.Unavailable = true
+				continue
+			}
+			return false, err
+		}
+
+		if item.Price != product.Price {
+			item.PreviousPrice = item.Price
+			item.Price = product.Price
+			changed = true
+		}
+		if item.Unavailable || item.Name != product.Name || item.Category != product.Category {
+			item.Unavailable = false
+			item.Name = product.Name
+			item.Category = product.Category
+			changed = true
+		}
+	}
+	return changed, nil
+}
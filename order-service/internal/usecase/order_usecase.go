@@ -1,53 +1,218 @@
 package usecase
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
 	"order-service/internal/entity"
 	"order-service/internal/repository"
 	"time"
 )
 
 type OrderUseCase interface {
-	CreateOrder(order *entity.Order) error
+	CreateOrder(order *entity.Order, actor entity.Actor, idempotencyKey string) error
 	GetOrder(id string) (*entity.Order, error)
-	UpdateOrderStatus(id string, status entity.OrderStatus) error
-	ListOrders(filter entity.OrderFilter) ([]entity.Order, error)
+	UpdateOrderStatus(id string, status entity.OrderStatus, reason string, actor entity.Actor) error
+	CancelOrder(id string, reason string, actor entity.Actor) error
+	ListOrders(filter entity.OrderFilter) (*entity.OrderPage, error)
 }
 
 type orderUseCase struct {
-	orderRepo repository.OrderRepository
+	orderRepo      repository.OrderRepository
+	catalog        repository.ProductCatalog
+	idempotencyTTL time.Duration
 }
 
-func NewOrderUseCase(orderRepo repository.OrderRepository) OrderUseCase {
+func NewOrderUseCase(orderRepo repository.OrderRepository, catalog repository.ProductCatalog, idempotencyTTL time.Duration) OrderUseCase {
 	return &orderUseCase{
-		orderRepo: orderRepo,
+		orderRepo:      orderRepo,
+		catalog:        catalog,
+		idempotencyTTL: idempotencyTTL,
 	}
 }
 
-func (uc *orderUseCase) CreateOrder(order *entity.Order) error {
-	// Calculate total if not set
-	if order.Total == 0 {
-		for _, item := range order.Items {
-			order.Total += item.Price * float64(item.Quantity)
+// CreateOrder prices the order from the catalog and stores it. A total set
+// by the caller is only checked against the computed one: on a mismatch the
+// priced order is left in place for the caller to quote and
+// ErrTotalMismatch is returned.
+//
+// With an idempotency key, a repeat of the request the key was first used
+// with fills in the order created back then instead of creating another one,
+// and a different request with the same key fails with
+// ErrIdempotencyKeyReused.
+func (uc *orderUseCase) CreateOrder(order *entity.Order, actor entity.Actor, idempotencyKey string) error {
+	var idempotency *entity.IdempotencyRecord
+	if idempotencyKey != "" {
+		if len(idempotencyKey) > 255 {
+			return entity.ErrInvalidIdempotencyKey
+		}
+		idempotency = &entity.IdempotencyRecord{
+			UserID:      order.UserID,
+			Key:         idempotencyKey,
+			RequestHash: requestHash(order),
+			ExpiresAt:   time.Now().Add(uc.idempotencyTTL),
+		}
+		if replayed, err := uc.replay(order, idempotency); replayed || err != nil {
+			return err
 		}
 	}
-	
+
+	clientTotal := order.Total
+	if err := uc.price(order); err != nil {
+		return err
+	}
+	if clientTotal != 0 && math.Abs(clientTotal-order.Total) >= 0.005 {
+		return fmt.Errorf("%w: got %.2f, expected %.2f", entity.ErrTotalMismatch, clientTotal, order.Total)
+	}
+
 	// Set timestamps
 	now := time.Now().Unix()
 	order.CreatedAt = now
 	order.UpdatedAt = now
+
+	order.StatusHistory = []entity.StatusChange{{
+		To:        order.Status,
+		ChangedAt: now,
+		Actor:     actor,
+		Reason:    "order created",
+	}}
 	
-	return uc.orderRepo.Create(order)
+	err := uc.orderRepo.Create(order, idempotency)
+	if errors.Is(err, entity.ErrDuplicateIdempotencyKey) {
+		// A concurrent request with the same key won; answer with its order,
+		// or with the error if it cannot be read yet
+		if replayed, err := uc.replay(order, idempotency); replayed || err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// replay fills in the order stored for the key of idempotency, if any, and
+// reports whether it did.
+func (uc *orderUseCase) replay(order *entity.Order, idempotency *entity.IdempotencyRecord) (bool, error) {
+	record, err := uc.orderRepo.FindByIdempotencyKey(idempotency.UserID, idempotency.Key)
+	if err != nil || record == nil {
+		return false, err
+	}
+	if record.RequestHash != idempotency.RequestHash {
+		return false, entity.ErrIdempotencyKeyReused
+	}
+	*order = record.Order
+	return true, nil
+}
+
+// requestHash identifies an order request by what the client sent: the user,
+// the items in order and the expected total. Client prices are left out,
+// since they are ignored anyway.
+func requestHash(order *entity.Order) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%.2f\n", order.UserID, order.Total)
+	for _, item := range order.Items {
+		fmt.Fprintf(h, "%s:%d\n", item.ProductID, item.Quantity)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+
+// price snapshots the current unit price, name and category of every item
+// and computes the subtotals and total. Client-supplied prices are ignored.
+// Repeated lines for a product are merged into the first, since stock is
+// reserved once per product.
+func (uc *orderUseCase) price(order *entity.Order) error {
+	if len(order.Items) == 0 {
+		return entity.ErrEmptyOrder
+	}
+
+	items := make([]entity.OrderItem, 0, len(order.Items))
+	lines := make(map[string]int, len(order.Items))
+	for _, item := range order.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: %s", entity.ErrInvalidQuantity, item.ProductID)
+		}
+		if i, ok := lines[item.ProductID]; ok {
+			items[i].Quantity += item.Quantity
+			continue
+		}
+		lines[item.ProductID] = len(items)
+		items = append(items, item)
+	}
+	order.Items = items
+
+	order.Subtotal = 0
+	for i := range order.Items {
+		item := &order.Items[i]
+		product, err := uc.catalog.GetProduct(item.ProductID)
+		if err != nil {
+			return err
+		}
+		item.Price = product.Price
+		item.Name = product.Name
+		item.Category = product.Category
+		item.Subtotal = roundCents(product.Price * float64(item.Quantity))
+		order.Subtotal += item.Subtotal
+	}
+
+	order.Subtotal = roundCents(order.Subtotal)
+	order.Total = order.Subtotal
+	return nil
 }
 
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
 
 func (uc *orderUseCase) GetOrder(id string) (*entity.Order, error) {
     return uc.orderRepo.FindByID(id)
 }
 
-func (uc *orderUseCase) UpdateOrderStatus(id string, status entity.OrderStatus) error {
-    return uc.orderRepo.UpdateStatus(id, status)
+// UpdateOrderStatus applies a status change allowed by the order lifecycle
+// and records why it happened.
+func (uc *orderUseCase) UpdateOrderStatus(id string, status entity.OrderStatus, reason string, actor entity.Actor) error {
+    order, err := uc.orderRepo.FindByID(id)
+    if err != nil {
+        return err
+    }
+
+    if !order.Status.CanTransitionTo(status) {
+        return fmt.Errorf("%w: %s -> %s", entity.ErrInvalidTransition, order.Status, status)
+    }
+
+    return uc.orderRepo.UpdateStatus(id, entity.StatusChange{
+        From:      order.Status,
+        To:        status,
+        ChangedAt: time.Now().Unix(),
+        Actor:     actor,
+        Reason:    reason,
+    })
+}
+
+// CancelOrder cancels an order that has not been paid yet. The repository
+// publishes order.cancelled, on which consumer-service gives back the stock.
+func (uc *orderUseCase) CancelOrder(id string, reason string, actor entity.Actor) error {
+    order, err := uc.orderRepo.FindByID(id)
+    if err != nil {
+        return err
+    }
+
+    if !order.Status.CanTransitionTo(entity.OrderStatusCancelled) {
+        return fmt.Errorf("%w: a %s order cannot be cancelled", entity.ErrInvalidTransition, order.Status)
+    }
+    if reason == "" {
+        reason = "cancelled by " + string(actor.Type)
+    }
+
+    return uc.orderRepo.UpdateStatus(id, entity.StatusChange{
+        From:      order.Status,
+        To:        entity.OrderStatusCancelled,
+        ChangedAt: time.Now().Unix(),
+        Actor:     actor,
+        Reason:    reason,
+    })
 }
 
-func (uc *orderUseCase) ListOrders(filter entity.OrderFilter) ([]entity.Order, error) {
+func (uc *orderUseCase) ListOrders(filter entity.OrderFilter) (*entity.OrderPage, error) {
     return uc.orderRepo.FindAll(filter)
 }
@@ -0,0 +1,5 @@
+module paging
+
+go 1.23.4
+
+require go.mongodb.org/mongo-driver v1.17.3
@@ -0,0 +1,59 @@
+package paging
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Order returns the sort document for s, with the ID as tie-breaker.
+func (s Sort) Order() bson.D {
+	dir := 1
+	if s.Desc {
+		dir = -1
+	}
+	if s.Field == "_id" {
+		return bson.D{{Key: "_id", Value: dir}}
+	}
+	return bson.D{{Key: s.Field, Value: dir}, {Key: "_id", Value: dir}}
+}
+
+// After returns the condition matching the documents that come after the
+// page token in the order s. Documents must have ObjectID IDs.
+func (s Sort) After(token string) (bson.M, error) {
+	cursor, err := DecodeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.OrderBy != s.String() {
+		return nil, ErrInvalidToken
+	}
+	afterID, err := primitive.ObjectIDFromHex(cursor.AfterID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	op := "$gt"
+	if s.Desc {
+		op = "$lt"
+	}
+	if s.Field == "_id" {
+		return bson.M{"_id": bson.M{op: afterID}}, nil
+	}
+	if cursor.Value == nil {
+		return nil, ErrInvalidToken
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{s.Field: bson.M{op: cursor.Value}},
+		bson.M{s.Field: cursor.Value, "_id": bson.M{op: afterID}},
+	}}, nil
+}
+
+// NextToken returns the token resuming after a document with the given ID
+// and sort value.
+func (s Sort) NextToken(id string, value interface{}) string {
+	cursor := Cursor{OrderBy: s.String(), AfterID: id}
+	if s.Field != "_id" {
+		cursor.Value = value
+	}
+	return EncodeToken(cursor)
+}
@@ -0,0 +1,108 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testID = "65f1c2a4b7e3d9a1c0ffee01"
+
+func objectID(t *testing.T) primitive.ObjectID {
+	t.Helper()
+	id, err := primitive.ObjectIDFromHex(testID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestSortOrderEndsWithID(t *testing.T) {
+	tests := []struct {
+		sort Sort
+		want bson.D
+	}{
+		{Sort{Field: "_id"}, bson.D{{Key: "_id", Value: 1}}},
+		{Sort{Field: "_id", Desc: true}, bson.D{{Key: "_id", Value: -1}}},
+		{Sort{Field: "price"}, bson.D{{Key: "price", Value: 1}, {Key: "_id", Value: 1}}},
+		{Sort{Field: "price", Desc: true}, bson.D{{Key: "price", Value: -1}, {Key: "_id", Value: -1}}},
+	}
+	for _, tt := range tests {
+		if got := tt.sort.Order(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v.Order() = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestAfterByID(t *testing.T) {
+	for _, tt := range []struct {
+		sort Sort
+		op   string
+	}{
+		{Sort{Field: "_id"}, "$gt"},
+		{Sort{Field: "_id", Desc: true}, "$lt"},
+	} {
+		token := tt.sort.NextToken(testID, nil)
+		got, err := tt.sort.After(token)
+		if err != nil {
+			t.Fatalf("%v.After returned %v", tt.sort, err)
+		}
+		want := bson.M{"_id": bson.M{tt.op: objectID(t)}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v.After = %v, want %v", tt.sort, got, want)
+		}
+	}
+}
+
+// Items sharing the sort value of the last item are continued by ID, so none
+// of them is skipped or repeated.
+func TestAfterBreaksTiesByID(t *testing.T) {
+	for _, tt := range []struct {
+		sort Sort
+		op   string
+	}{
+		{Sort{Field: "price"}, "$gt"},
+		{Sort{Field: "price", Desc: true}, "$lt"},
+	} {
+		token := tt.sort.NextToken(testID, 9.99)
+		got, err := tt.sort.After(token)
+		if err != nil {
+			t.Fatalf("%v.After returned %v", tt.sort, err)
+		}
+		want := bson.M{"$or": bson.A{
+			bson.M{"price": bson.M{tt.op: 9.99}},
+			bson.M{"price": 9.99, "_id": bson.M{tt.op: objectID(t)}},
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v.After = %v, want %v", tt.sort, got, want)
+		}
+	}
+}
+
+func TestAfterRejectsTokenOfOtherOrdering(t *testing.T) {
+	asc := Sort{Field: "price"}
+	desc := Sort{Field: "price", Desc: true}
+	byName := Sort{Field: "name"}
+
+	token := asc.NextToken(testID, 9.99)
+	for _, other := range []Sort{desc, byName, {Field: "_id"}} {
+		if _, err := other.After(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%v.After(token of %v) returned %v, want %v", other, asc, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestAfterRejectsMalformedCursor(t *testing.T) {
+	s := Sort{Field: "price"}
+	for _, cursor := range []Cursor{
+		{OrderBy: "price", AfterID: "not-an-object-id", Value: 1.0},
+		{OrderBy: "price", AfterID: testID},
+	} {
+		if _, err := s.After(EncodeToken(cursor)); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("After(%+v) returned %v, want %v", cursor, err, ErrInvalidToken)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package paging holds the cursor pagination and sorting shared by the
+// listings of the services: page sizes, opaque page tokens, order_by parsing
+// and the MongoDB queries that resume after a token.
+package paging
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultSize = 20
+	MaxSize     = 100
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid page token")
+	ErrInvalidOrderBy = errors.New("invalid order_by")
+)
+
+// Cursor is the position a page token resumes after: the sort value and ID
+// of the last item of the previous page. Tokens are opaque to clients; only
+// the repository that issued them reads them.
+type Cursor struct {
+	// OrderBy is the ordering the token was issued for; it is only valid
+	// for the same ordering.
+	OrderBy string      `json:"order_by,omitempty"`
+	Value   interface{} `json:"value,omitempty"`
+	AfterID string      `json:"after"`
+}
+
+// Sort is a parsed order_by. Listings always break ties by ID, so the order
+// is stable and a page token marks an exact position.
+type Sort struct {
+	// Field is the bson field sorted on; "_id" when sorting by ID alone.
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses an order_by of the form "field" or "field asc|desc".
+// fields maps the names clients may sort on to bson fields. An empty
+// order_by sorts by ID, ascending.
+func ParseSort(orderBy string, fields map[string]string) (Sort, error) {
+	parts := strings.Fields(orderBy)
+	if len(parts) == 0 {
+		return Sort{Field: "_id"}, nil
+	}
+	if len(parts) > 2 {
+		return Sort{}, fmt.Errorf("%w: %q", ErrInvalidOrderBy, orderBy)
+	}
+
+	field, ok := fields[parts[0]]
+	if !ok {
+		return Sort{}, fmt.Errorf("%w: cannot sort by %q", ErrInvalidOrderBy, parts[0])
+	}
+	sort := Sort{Field: field}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc":
+		case "desc":
+			sort.Desc = true
+		default:
+			return Sort{}, fmt.Errorf("%w: unknown direction %q", ErrInvalidOrderBy, parts[1])
+		}
+	}
+	return sort, nil
+}
+
+// String is the canonical form of the sort, used to tie page tokens to it.
+func (s Sort) String() string {
+	if s.Desc {
+		return s.Field + " desc"
+	}
+	return s.Field
+}
+
+// EncodeToken turns a cursor into a page token.
+func EncodeToken(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeToken reads a token made by EncodeToken.
+func DecodeToken(token string) (Cursor, error) {
+	var cursor Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &cursor) != nil || cursor.AfterID == "" {
+		return Cursor{}, ErrInvalidToken
+	}
+	return cursor, nil
+}
+
+// Size turns a requested limit into the page size to use.
+func Size(limit int) int {
+	if limit <= 0 {
+		return DefaultSize
+	}
+	if limit > MaxSize {
+		return MaxSize
+	}
+	return limit
+}
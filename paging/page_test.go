@@ -0,0 +1,69 @@
+package paging
+
+import (
+	"errors"
+	"testing"
+)
+
+var testFields = map[string]string{
+	"created_at": "_id",
+	"price":      "price",
+	"name":       "name",
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    Sort
+	}{
+		{"", Sort{Field: "_id"}},
+		{"price", Sort{Field: "price"}},
+		{"price asc", Sort{Field: "price"}},
+		{"price desc", Sort{Field: "price", Desc: true}},
+		{"  name   DESC ", Sort{Field: "name", Desc: true}},
+		{"created_at desc", Sort{Field: "_id", Desc: true}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.orderBy, testFields)
+		if err != nil {
+			t.Errorf("ParseSort(%q) returned %v", tt.orderBy, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSort(%q) = %+v, want %+v", tt.orderBy, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortRejectsInvalidOrderBy(t *testing.T) {
+	for _, orderBy := range []string{"stock", "price sideways", "price desc name", "_id"} {
+		if _, err := ParseSort(orderBy, testFields); !errors.Is(err, ErrInvalidOrderBy) {
+			t.Errorf("ParseSort(%q) returned %v, want %v", orderBy, err, ErrInvalidOrderBy)
+		}
+	}
+}
+
+func TestSortString(t *testing.T) {
+	if got := (Sort{Field: "price"}).String(); got != "price" {
+		t.Errorf("ascending sort = %q, want %q", got, "price")
+	}
+	if got := (Sort{Field: "price", Desc: true}).String(); got != "price desc" {
+		t.Errorf("descending sort = %q, want %q", got, "price desc")
+	}
+}
+
+func TestDecodeTokenRejectsGarbage(t *testing.T) {
+	for _, token := range []string{"", "not base64!", EncodeToken(Cursor{OrderBy: "price"})} {
+		if _, err := DecodeToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("DecodeToken(%q) returned %v, want %v", token, err, ErrInvalidToken)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	for limit, want := range map[int]int{-1: DefaultSize, 0: DefaultSize, 5: 5, MaxSize: MaxSize, MaxSize + 1: MaxSize} {
+		if got := Size(limit); got != want {
+			t.Errorf("Size(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
@@ -2,43 +2,56 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
 	"github.com/nats-io/nats.go"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-
-	pb "producer-service/proto"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"events"
+	"producer-service/internal/changestream"
+	"producer-service/internal/config"
+	"producer-service/internal/outbox"
 )
 
 func main() {
+	cfg := config.NewConfig()
+
 	// Connect to NATS
-	nc, err := nats.Connect(nats.DefaultURL)
+	nc, err := nats.Connect(cfg.NATSURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to NATS: %v", err)
 	}
 	defer nc.Close()
 
-	// Connect to Order Service
-	conn, err := grpc.Dial("localhost:8081", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	js, err := jetstream.New(nc)
 	if err != nil {
-		log.Fatalf("Failed to connect to order service: %v", err)
+		log.Fatalf("Failed to create JetStream context: %v", err)
 	}
-	defer conn.Close()
 
-	orderClient := pb.NewOrderServiceClient(conn)
+	// Connect to the order database, whose events we publish
+	connectCtx, connectCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer connectCancel()
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(cfg.MongoDBURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatalf("Failed to ping MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
 
-	// Track published orders
-	var publishedOrders sync.Map
-	lastChecked := time.Now().Add(-1 * time.Minute) // Start by checking recent orders
+	// Make sure the stream that stores order events exists
+	if err := events.EnsureStream(connectCtx, js, cfg.StreamName, cfg.StreamSubjects); err != nil {
+		log.Fatalf("Failed to set up %s stream: %v", cfg.StreamName, err)
+	}
 
-	// Create a context for the stream
+	// Create a context for the publisher
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -46,62 +59,29 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start order processing loop
-	go func() {
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				// Get orders created since last check
-				req := &pb.ListOrdersRequest{
-					Status: "pending",
-					Limit:  100,
-					// Add timestamp filter to only get new orders
-					CreatedAfter: lastChecked.Unix(),
-				}
-
-				// Update last checked time
-				lastChecked = time.Now()
-
-				stream, err := orderClient.ListOrders(ctx, req)
-				if err != nil {
-					log.Printf("Failed to get orders: %v", err)
-					continue
-				}
-
-				for {
-					res, err := stream.Recv()
-					if err != nil {
-						// EOF is normal when stream ends
-						break
-					}
-
-					for _, order := range res.Orders {
-						if _, loaded := publishedOrders.LoadOrStore(order.Id, true); !loaded {
-							orderData, err := json.Marshal(order)
-							if err != nil {
-								log.Printf("Failed to marshal order: %v", err)
-								continue
-							}
+	// Start publishing order events
+	db := client.Database(cfg.MongoDBName)
+	publisher := outbox.NewJetStreamPublisher(js)
+	var run func(context.Context)
+	switch cfg.PublishMode {
+	case config.PublishModeChangeStream:
+		run = changestream.NewPublisher(db, publisher).Run
+	case config.PublishModeOutbox:
+		run = outbox.NewRelay(db, publisher, cfg.OutboxPollInterval, cfg.OutboxBatchSize).Run
+	default:
+		log.Fatalf("Unknown publish mode %q", cfg.PublishMode)
+	}
+	log.Printf("Publishing order events in %s mode", cfg.PublishMode)
 
-							if err := nc.Publish("order.created", orderData); err != nil {
-								log.Printf("Failed to publish event: %v", err)
-								publishedOrders.Delete(order.Id) // Allow retry
-							} else {
-								log.Printf("Published order %s", order.Id)
-							}
-						}
-					}
-				}
-			}
-		}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(ctx)
 	}()
 
 	log.Println("Producer service running...")
 	<-quit
 	log.Println("Shutting down producer-service...")
-}
\ No newline at end of file
+	cancel()
+	<-done
+}
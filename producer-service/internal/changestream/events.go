@@ -0,0 +1,74 @@
+package changestream
+
+import (
+	eventspb "events/proto"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// orderDocument is the subset of an order-service order document that the
+// events are built from.
+type orderDocument struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	UserID string             `bson:"user_id"`
+	Items  []struct {
+		ProductID string  `bson:"product_id"`
+		Quantity  int     `bson:"quantity"`
+		Price     float64 `bson:"price"`
+		Name      string  `bson:"name"`
+		Category  string  `bson:"category"`
+		Subtotal  float64 `bson:"subtotal"`
+	} `bson:"items"`
+	Subtotal  float64 `bson:"subtotal"`
+	Total     float64 `bson:"total"`
+	Status    string  `bson:"status"`
+	CreatedAt int64   `bson:"created_at"`
+	UpdatedAt int64   `bson:"updated_at"`
+}
+
+type statusChange struct {
+	From      string `bson:"from"`
+	To        string `bson:"to"`
+	ChangedAt int64  `bson:"changed_at"`
+	Actor     struct {
+		Type string `bson:"type"`
+		ID   string `bson:"id"`
+	} `bson:"actor"`
+	Reason string `bson:"reason"`
+}
+
+func orderCreatedEvent(doc *orderDocument) *eventspb.OrderCreated {
+	event := &eventspb.OrderCreated{
+		OrderId:   doc.ID.Hex(),
+		UserId:    doc.UserID,
+		Subtotal:  doc.Subtotal,
+		Total:     doc.Total,
+		Status:    doc.Status,
+		CreatedAt: doc.CreatedAt,
+		UpdatedAt: doc.UpdatedAt,
+	}
+	for _, item := range doc.Items {
+		event.Items = append(event.Items, &eventspb.OrderItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+			Price:     item.Price,
+			Name:      item.Name,
+			Category:  item.Category,
+			Subtotal:  item.Subtotal,
+		})
+	}
+	return event
+}
+
+func orderCancelledEvent(doc *orderDocument, change *statusChange) *eventspb.OrderCancelled {
+	return &eventspb.OrderCancelled{
+		OrderId:     doc.ID.Hex(),
+		UserId:      doc.UserID,
+		FromStatus:  change.From,
+		Reason:      change.Reason,
+		ActorType:   change.Actor.Type,
+		ActorId:     change.Actor.ID,
+		CancelledAt: change.ChangedAt,
+		Items:       orderCreatedEvent(doc).Items,
+	}
+}
@@ -0,0 +1,241 @@
+package changestream
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"events"
+	eventspb "events/proto"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"producer-service/internal/outbox"
+)
+
+const (
+	checkpointID = "orders"
+
+	// eventSource identifies change-stream events, which are derived from
+	// order-service's data rather than written by it.
+	eventSource = "producer-service"
+)
+
+// changeEvent is the part of a MongoDB change event the publisher needs.
+type changeEvent struct {
+	OperationType     string         `bson:"operationType"`
+	FullDocument      *orderDocument `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.Raw `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+// Publisher turns inserts and status updates on the orders collection into
+// order.created and order.status_changed events. The resume token of the last
+// published change is stored in the stream_checkpoints collection, so a
+// restart continues right after it.
+type Publisher struct {
+	orders      *mongo.Collection
+	checkpoints *mongo.Collection
+	publisher   outbox.Publisher
+	retryDelay  time.Duration
+}
+
+func NewPublisher(db *mongo.Database, publisher outbox.Publisher) *Publisher {
+	return &Publisher{
+		orders:      db.Collection("orders"),
+		checkpoints: db.Collection("stream_checkpoints"),
+		publisher:   publisher,
+		retryDelay:  5 * time.Second,
+	}
+}
+
+// Run watches the orders collection until ctx is cancelled, reopening the
+// stream from the last checkpoint after errors.
+func (p *Publisher) Run(ctx context.Context) {
+	for {
+		err := p.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Change stream error, reopening in %s: %v", p.retryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.retryDelay):
+		}
+	}
+}
+
+func (p *Publisher) watch(ctx context.Context) error {
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	token, err := p.loadResumeToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		opts.SetResumeAfter(token)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": bson.A{"insert", "update"}}}}},
+	}
+	stream, err := p.orders.Watch(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	log.Println("Watching order_db.orders change stream")
+	for stream.Next(ctx) {
+		var event changeEvent
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+		if err := p.publish(&event); err != nil {
+			return err
+		}
+		if err := p.saveResumeToken(ctx, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+	return stream.Err()
+}
+
+func (p *Publisher) publish(event *changeEvent) error {
+	if event.FullDocument == nil {
+		// The order was deleted before the update could be looked up
+		return nil
+	}
+	doc := event.FullDocument
+	orderID := doc.ID.Hex()
+
+	switch event.OperationType {
+	case "insert":
+		id := orderID + ":created"
+		data, err := events.Encode(events.Meta{
+			ID:            id,
+			Type:          events.TypeOrderCreated,
+			Source:        eventSource,
+			Subject:       orderID,
+			Time:          time.Unix(doc.CreatedAt, 0),
+			CorrelationID: orderID,
+		}, orderCreatedEvent(doc))
+		if err != nil {
+			return err
+		}
+		return p.publisher.Publish(events.TypeOrderCreated, id, data)
+
+	case "update":
+		change, ok, err := pushedStatusChange(event.UpdateDescription.UpdatedFields)
+		if err != nil || !ok {
+			return err
+		}
+		id := fmt.Sprintf("%s:%s:%d", orderID, change.To, change.ChangedAt)
+		data, err := events.Encode(events.Meta{
+			ID:            id,
+			Type:          events.TypeOrderStatusChanged,
+			Source:        eventSource,
+			Subject:       orderID,
+			Time:          time.Unix(change.ChangedAt, 0),
+			CorrelationID: orderID,
+		}, &eventspb.OrderStatusChanged{
+			OrderId:    orderID,
+			UserId:     doc.UserID,
+			FromStatus: change.From,
+			ToStatus:   change.To,
+			Reason:     change.Reason,
+			ActorType:  change.Actor.Type,
+			ActorId:    change.Actor.ID,
+			ChangedAt:  change.ChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := p.publisher.Publish(events.TypeOrderStatusChanged, id, data); err != nil {
+			return err
+		}
+		if change.To != "cancelled" {
+			return nil
+		}
+
+		// A retry republishes the status change as well; the stream drops it
+		// as a duplicate of the same message ID.
+		id = orderID + ":cancelled"
+		data, err = events.Encode(events.Meta{
+			ID:            id,
+			Type:          events.TypeOrderCancelled,
+			Source:        eventSource,
+			Subject:       orderID,
+			Time:          time.Unix(change.ChangedAt, 0),
+			CorrelationID: orderID,
+		}, orderCancelledEvent(doc, change))
+		if err != nil {
+			return err
+		}
+		return p.publisher.Publish(events.TypeOrderCancelled, id, data)
+	}
+	return nil
+}
+
+// pushedStatusChange finds the status history entry appended by an update.
+// MongoDB reports a $push either as "status_history.<n>" or, when it rewrites
+// the array, as the whole "status_history" field. In the latter case the
+// pushed entry is the last one; it may also be the only one, when the order
+// predates the history. An entry without a from status is the one an order is
+// created with, which is not a change.
+func pushedStatusChange(updated bson.Raw) (*statusChange, bool, error) {
+	elements, err := updated.Elements()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, element := range elements {
+		key := element.Key()
+		switch {
+		case strings.HasPrefix(key, "status_history."):
+			var change statusChange
+			if err := element.Value().Unmarshal(&change); err != nil {
+				return nil, false, err
+			}
+			return &change, true, nil
+		case key == "status_history":
+			var history []statusChange
+			if err := element.Value().Unmarshal(&history); err != nil {
+				return nil, false, err
+			}
+			if len(history) > 0 && history[len(history)-1].From != "" {
+				return &history[len(history)-1], true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+func (p *Publisher) loadResumeToken(ctx context.Context) (bson.Raw, error) {
+	var checkpoint struct {
+		Token bson.Raw `bson:"token"`
+	}
+	err := p.checkpoints.FindOne(ctx, bson.M{"_id": checkpointID}).Decode(&checkpoint)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return checkpoint.Token, nil
+}
+
+func (p *Publisher) saveResumeToken(ctx context.Context, token bson.Raw) error {
+	_, err := p.checkpoints.UpdateOne(ctx,
+		bson.M{"_id": checkpointID},
+		bson.M{"$set": bson.M{"token": token, "updated_at": time.Now().Unix()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
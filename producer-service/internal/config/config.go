@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Publish modes. "outbox" polls the outbox collection order-service writes
+// in its transactions; "changestream" watches the orders collection instead.
+const (
+	PublishModeOutbox       = "outbox"
+	PublishModeChangeStream = "changestream"
+)
+
+type Config struct {
+	NATSURL     string
+	MongoDBURI  string
+	MongoDBName string
+
+	PublishMode string
+
+	StreamName     string
+	StreamSubjects []string
+
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+}
+
+func NewConfig() *Config {
+	return &Config{
+		NATSURL:     getEnv("NATS_URL", "nats://127.0.0.1:4222"),
+		MongoDBURI:  getEnv("ORDER_DB_URI", "mongodb://localhost:27017"),
+		MongoDBName: getEnv("ORDER_DB_NAME", "order_db"),
+
+		PublishMode: getEnv("PUBLISH_MODE", PublishModeOutbox),
+
+		StreamName:     getEnv("ORDERS_STREAM", "ORDERS"),
+		StreamSubjects: []string{"order.>"},
+
+		OutboxPollInterval: getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    getEnvInt("OUTBOX_BATCH_SIZE", 100),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
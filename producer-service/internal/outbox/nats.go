@@ -0,0 +1,30 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type jetStreamPublisher struct {
+	js jetstream.JetStream
+}
+
+// NewJetStreamPublisher publishes to JetStream and only reports success once
+// the stream has stored the message. The event ID is sent as Nats-Msg-Id so
+// the stream drops duplicates of a retried publish.
+func NewJetStreamPublisher(js jetstream.JetStream) Publisher {
+	return &jetStreamPublisher{js: js}
+}
+
+func (p *jetStreamPublisher) Publish(subject, id string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	_, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(id))
+	return err
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Event is an entry of order-service's outbox collection.
+type Event struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	AggregateID string             `bson:"aggregate_id"`
+	Type        string             `bson:"type"`
+	Payload     []byte             `bson:"payload"`
+	CreatedAt   int64              `bson:"created_at"`
+}
+
+// Publisher delivers an event to the broker. It must only return nil once the
+// broker has the message. id identifies the event so consumers can drop
+// duplicates.
+type Publisher interface {
+	Publish(subject, id string, data []byte) error
+}
+
+// Relay publishes outbox events in insertion order and deletes each one after
+// it was published, so the outbox only holds unsent events. A crash between
+// the two steps publishes the event again, so delivery is at-least-once.
+type Relay struct {
+	collection *mongo.Collection
+	publisher  Publisher
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewRelay(db *mongo.Database, publisher Publisher, interval time.Duration, batchSize int) *Relay {
+	return &Relay{
+		collection: db.Collection("outbox"),
+		publisher:  publisher,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	// Earlier versions kept sent events and marked them with sent_at
+	if res, err := r.collection.DeleteMany(ctx, bson.M{"sent_at": bson.M{"$exists": true}}); err != nil {
+		log.Printf("Failed to delete sent outbox events: %v", err)
+	} else if res.DeletedCount > 0 {
+		log.Printf("Deleted %d sent outbox events", res.DeletedCount)
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while batches come back full
+		for {
+			n, err := r.RelayBatch(ctx)
+			if err != nil {
+				log.Printf("Outbox relay error: %v", err)
+				break
+			}
+			if n < r.batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publishes up to batchSize events and returns how many were
+// published. It stops at the first failure so later events never overtake an
+// earlier one.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(r.batchSize))
+
+	// Skip events an earlier version marked sent, should Run have failed to
+	// delete them
+	cursor, err := r.collection.Find(ctx, bson.M{"sent_at": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	var events []Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if err := r.publisher.Publish(event.Type, event.ID.Hex(), event.Payload); err != nil {
+			return i, err
+		}
+
+		_, err := r.collection.DeleteOne(ctx, bson.M{"_id": event.ID})
+		if err != nil {
+			return i, err
+		}
+		log.Printf("Published %s for order %s", event.Type, event.AggregateID)
+	}
+	return len(events), nil
+}
@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
@@ -16,6 +17,8 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"authz"
+
 	"user-service/internal/config"
 	"user-service/internal/controller"
 	"user-service/internal/repository"
@@ -31,6 +34,30 @@ func main() {
 	// Create EmailService
 	emailService := service.NewEmailService(cfg) // ✅ Added this
 
+	// Create TokenService for signing access tokens
+	tokenService, err := service.NewTokenService(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize token service: %v", err)
+	}
+
+	// Create Verifier for the tokens callers send
+	verifier, err := authz.NewVerifier(authz.VerifierConfig{
+		Algorithm:     cfg.JWTAlgorithm,
+		Secret:        cfg.JWTSecret,
+		PublicKeyPath: cfg.JWTPublicKeyPath,
+		Issuer:        cfg.JWTIssuer,
+		ServiceSecret: cfg.ServiceTokenSecret,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize token verifier: %v", err)
+	}
+
+	// Create PasswordHasher for storing and checking passwords
+	passwordHasher, err := service.NewPasswordHasher(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize password hasher: %v", err)
+	}
+
 	// Connect to MongoDB
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -45,19 +72,38 @@ func main() {
 		}
 	}()
 
-	// Initialize repository
+	// Connect to Redis for the access token denylist
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+	defer redisClient.Close()
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+
+	// Initialize repositories
 	userRepo := repository.NewUserRepository(client.Database(cfg.DatabaseName).Collection("users"))
+	refreshTokenCollection := client.Database(cfg.DatabaseName).Collection("refresh_tokens")
+	if err := repository.EnsureRefreshTokenIndexes(ctx, refreshTokenCollection); err != nil {
+		log.Fatalf("Failed to create refresh token indexes: %v", err)
+	}
+	refreshTokenRepo := repository.NewRefreshTokenRepository(refreshTokenCollection)
+	denylistRepo := repository.NewTokenDenylistRepository(redisClient)
 
 	// Initialize use case with email service
-	userUseCase := usecase.NewUserUseCase(interface{}(userRepo).(repository.UserRepository), emailService) // cast to interface if needed
+	userUseCase := usecase.NewUserUseCase(interface{}(userRepo).(repository.UserRepository), emailService, passwordHasher) // cast to interface if needed
+
+	sessionUseCase := usecase.NewSessionUseCase(userRepo, refreshTokenRepo, denylistRepo, tokenService, cfg.RefreshTokenTTL)
 
 	// Create gRPC server
 	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(loggingInterceptor),
+		grpc.ChainUnaryInterceptor(loggingInterceptor, authz.UnaryServerInterceptor(verifier)),
 	)
 
 	// Register services
-	userController := controller.NewUserController(*userUseCase) // pass value instead of pointer
+	userController := controller.NewUserController(*userUseCase, sessionUseCase, tokenService) // pass value instead of pointer
 	pb.RegisterUserServiceServer(grpcServer, userController)
 
 	// Add health check service
@@ -3,6 +3,7 @@ package config
 import (
 	"context"
 	"os"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,9 +15,29 @@ type Config struct {
 	DatabaseURI  string
 	DatabaseName string
 	EmailFrom    string
-    EmailPass    string
-    SMTPHost     string
-    SMTPPort     string
+	EmailPass    string
+	SMTPHost     string
+	SMTPPort     string
+
+	JWTAlgorithm      string
+	JWTSecret         string
+	JWTPrivateKeyPath string
+	JWTPublicKeyPath  string
+	JWTIssuer         string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	// ServiceTokenSecret verifies the service tokens of the other services.
+	ServiceTokenSecret string
+
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+
+	PasswordHashAlgorithm string
+	BcryptCost            int
+	Argon2Memory          int // KiB
+	Argon2Iterations      int
+	Argon2Parallelism     int
 }
 
 func NewConfig() *Config {
@@ -25,9 +46,29 @@ func NewConfig() *Config {
 		DatabaseURI:  getEnv("USER_SERVICE_DB_URI", "mongodb://localhost:27017"),
 		DatabaseName: getEnv("USER_SERVICE_DB_NAME", "userdb"),
 		EmailFrom:    getEnv("EMAIL_FROM", "example@email.com"),
-        EmailPass:    getEnv("EMAIL_PASS", "password"), // Use app password
-        SMTPHost:     getEnv("SMTP_HOST", "smtp.gmail.com"),
-        SMTPPort:     getEnv("SMTP_PORT", "587"),
+		EmailPass:    getEnv("EMAIL_PASS", "password"), // Use app password
+		SMTPHost:     getEnv("SMTP_HOST", "smtp.gmail.com"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+
+		JWTAlgorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+		JWTSecret:         getEnv("JWT_SECRET", ""),
+		JWTPrivateKeyPath: getEnv("JWT_PRIVATE_KEY_PATH", ""),
+		JWTPublicKeyPath:  getEnv("JWT_PUBLIC_KEY_PATH", ""),
+		JWTIssuer:         getEnv("JWT_ISSUER", "user-service"),
+		AccessTokenTTL:    getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+		ServiceTokenSecret: getEnv("SERVICE_TOKEN_SECRET", ""),
+
+		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       0,
+
+		PasswordHashAlgorithm: getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+		BcryptCost:            getEnvInt("BCRYPT_COST", 12),
+		Argon2Memory:          getEnvInt("ARGON2_MEMORY_KIB", 64*1024),
+		Argon2Iterations:      getEnvInt("ARGON2_ITERATIONS", 3),
+		Argon2Parallelism:     getEnvInt("ARGON2_PARALLELISM", 2),
 	}
 }
 
@@ -38,6 +79,24 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
 func ConnectDatabase(uri string) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
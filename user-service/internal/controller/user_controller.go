@@ -4,22 +4,27 @@ import (
 	"context"
 	"errors"
 	"log"
+	"strings"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"user-service/internal/models"
+	"user-service/internal/service"
 	"user-service/internal/usecase"
 	pb "user-service/proto"
 )
 
 type UserController struct {
 	pb.UnimplementedUserServiceServer
-	useCase usecase.UserUseCase
+	useCase        usecase.UserUseCase
+	sessionUseCase *usecase.SessionUseCase
+	tokenService   *service.TokenService
 }
 
-func NewUserController(useCase usecase.UserUseCase) *UserController {
-	return &UserController{useCase: useCase}
+func NewUserController(useCase usecase.UserUseCase, sessionUseCase *usecase.SessionUseCase, tokenService *service.TokenService) *UserController {
+	return &UserController{useCase: useCase, sessionUseCase: sessionUseCase, tokenService: tokenService}
 }
 
 func (c *UserController) RegisterUser(ctx context.Context, req *pb.RegisterUserRequest) (*pb.UserResponse, error) {
@@ -62,13 +67,61 @@ func (c *UserController) AuthenticateUser(ctx context.Context, req *pb.Authentic
 		return nil, status.Error(codes.Internal, "authentication failed")
 	}
 
-	// In production, use a proper JWT token generator
-	token := "generated-jwt-token-" + user.ID.Hex()
+	tokens, err := c.sessionUseCase.StartSession(ctx, user)
+	if err != nil {
+		log.Printf("Failed to issue tokens: %v", err)
+		return nil, status.Error(codes.Internal, "authentication failed")
+	}
 
-	return &pb.AuthResponse{
-		Token:   token,
-		UserId: user.ID.Hex(),
-	}, nil
+	return authResponse(user, tokens), nil
+}
+
+func (c *UserController) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh token is required")
+	}
+
+	tokens, user, err := c.sessionUseCase.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) || errors.Is(err, usecase.ErrRefreshTokenReused) {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		log.Printf("Token refresh failed: %v", err)
+		return nil, status.Error(codes.Internal, "token refresh failed")
+	}
+
+	return authResponse(user, tokens), nil
+}
+
+func (c *UserController) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	claims, err := c.callerClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.sessionUseCase.Logout(ctx, claims, req.GetRefreshToken()); err != nil {
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			return nil, status.Error(codes.PermissionDenied, "refresh token belongs to another user")
+		}
+		log.Printf("Logout failed: %v", err)
+		return nil, status.Error(codes.Internal, "logout failed")
+	}
+
+	return &pb.LogoutResponse{Success: true}, nil
+}
+
+func (c *UserController) LogoutAllSessions(ctx context.Context, req *pb.LogoutAllSessionsRequest) (*pb.LogoutResponse, error) {
+	claims, err := c.callerClaims(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.sessionUseCase.LogoutAllSessions(ctx, claims); err != nil {
+		log.Printf("Logout of all sessions failed: %v", err)
+		return nil, status.Error(codes.Internal, "logout failed")
+	}
+
+	return &pb.LogoutResponse{Success: true}, nil
 }
 
 func (c *UserController) GetUserProfile(ctx context.Context, req *pb.GetUserProfileRequest) (*pb.UserResponse, error) {
@@ -92,6 +145,32 @@ func (c *UserController) GetUserProfile(ctx context.Context, req *pb.GetUserProf
 	}, nil
 }
 
+// callerClaims verifies the access token the gateway forwarded in the
+// authorization metadata.
+func (c *UserController) callerClaims(ctx context.Context) (*service.Claims, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+	}
+
+	claims, err := c.tokenService.ParseAccessToken(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+	return claims, nil
+}
+
+func authResponse(user *models.User, tokens *usecase.TokenPair) *pb.AuthResponse {
+	return &pb.AuthResponse{
+		Token:            tokens.AccessToken,
+		UserId:           user.ID.Hex(),
+		ExpiresAt:        tokens.AccessExpiresAt.Unix(),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt.Unix(),
+	}
+}
+
 func (c *UserController) HealthCheck(ctx context.Context, req *pb.HealthRequest) (*pb.HealthResponse, error) {
 	return &pb.HealthResponse{Status: "SERVING"}, nil
 }
\ No newline at end of file
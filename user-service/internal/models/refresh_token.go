@@ -0,0 +1,21 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// RefreshToken is a stored refresh token. Only the SHA-256 hash of the token
+// is kept. Every token issued by rotating another one shares its FamilyID, so
+// a whole login session can be revoked at once.
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     string             `bson:"user_id"`
+	FamilyID   string             `bson:"family_id"`
+	TokenHash  string             `bson:"token_hash"`
+	ExpiresAt  int64              `bson:"expires_at"`
+	CreatedAt  int64              `bson:"created_at"`
+	RevokedAt  int64              `bson:"revoked_at,omitempty"`
+	ReplacedBy string             `bson:"replaced_by,omitempty"`
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != 0
+}
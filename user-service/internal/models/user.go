@@ -2,11 +2,19 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// Roles carried in access tokens. New users are customers; admins are
+// promoted by setting the role on their record.
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
 type User struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty"`
 	Username string             `bson:"username"`
 	Password string             `bson:"password"`
 	Email    string             `bson:"email"`
+	Role     string             `bson:"role"`
 }
 
 func (u *User) GenerateID() {
@@ -15,4 +23,13 @@ func (u *User) GenerateID() {
 
 func (u *User) GetID() string {
 	return u.ID.Hex()
+}
+
+// GetRole returns the user's role, treating records created before roles
+// existed as customers.
+func (u *User) GetRole() string {
+	if u.Role == "" {
+		return RoleCustomer
+	}
+	return u.Role
 }
\ No newline at end of file
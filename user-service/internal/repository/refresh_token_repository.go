@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"user-service/internal/models"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	Rotate(ctx context.Context, hash, replacedBy string) (bool, error)
+	RevokeByHash(ctx context.Context, hash string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
+
+type refreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(collection *mongo.Collection) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		collection: collection,
+	}
+}
+
+// EnsureRefreshTokenIndexes creates the unique token hash index and a TTL
+// index so expired tokens are purged by MongoDB.
+func EnsureRefreshTokenIndexes(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "family_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at_date", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	// expires_at_date only exists for the TTL index, which needs a BSON date.
+	doc := struct {
+		models.RefreshToken `bson:",inline"`
+		ExpiresAtDate       time.Time `bson:"expires_at_date"`
+	}{*token, time.Unix(token.ExpiresAt, 0)}
+
+	res, err := r.collection.InsertOne(ctx, doc)
+	if err != nil {
+		return err
+	}
+	if id, ok := res.InsertedID.(primitive.ObjectID); ok {
+		token.ID = id
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate revokes the token and records its successor, but only if it has not
+// been revoked yet. It reports false when another request rotated it first.
+func (r *refreshTokenRepository) Rotate(ctx context.Context, hash, replacedBy string) (bool, error) {
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"token_hash": hash, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now().Unix(), "replaced_by": replacedBy}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeByHash(ctx context.Context, hash string) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"token_hash": hash, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}},
+	)
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"family_id": familyID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}},
+	)
+	return err
+}
+
+func (r *refreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}},
+	)
+	return err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// The gateway reads these keys to reject revoked access tokens.
+const (
+	denylistTokenPrefix = "denylist:jti:"
+	denylistUserPrefix  = "denylist:user:"
+)
+
+type TokenDenylistRepository interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeUserTokensBefore(ctx context.Context, userID string, before time.Time, ttl time.Duration) error
+}
+
+type tokenDenylistRepository struct {
+	client *redis.Client
+}
+
+func NewTokenDenylistRepository(client *redis.Client) TokenDenylistRepository {
+	return &tokenDenylistRepository{client: client}
+}
+
+// RevokeToken denylists a single access token until it would have expired
+// anyway.
+func (r *tokenDenylistRepository) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, denylistTokenPrefix+tokenID, 1, ttl).Err()
+}
+
+// RevokeUserTokensBefore denylists every access token of the user issued
+// before the given time, to the millisecond. ttl should be at least the
+// access token lifetime.
+func (r *tokenDenylistRepository) RevokeUserTokensBefore(ctx context.Context, userID string, before time.Time, ttl time.Duration) error {
+	return r.client.Set(ctx, denylistUserPrefix+userID, strconv.FormatInt(before.UnixMilli(), 10), ttl).Err()
+}
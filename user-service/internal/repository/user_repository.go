@@ -18,6 +18,7 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error
 }
 
 type userRepository struct {
@@ -71,3 +72,14 @@ func (r *userRepository) GetUserByUsername(ctx context.Context, username string)
 	}
 	return &user, nil
 }
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id primitive.ObjectID, passwordHash string) error {
+	res, err := r.collection.UpdateByID(ctx, id, bson.M{"$set": bson.M{"password": passwordHash}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"user-service/internal/config"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+
+	argon2idPrefix = "$argon2id$"
+	argon2SaltLen  = 16
+	argon2KeyLen   = 32
+)
+
+var ErrMalformedHash = errors.New("malformed password hash")
+
+// PasswordHasher hashes passwords for storage and checks them at login.
+// Stored hashes carry their algorithm and parameters as a prefix, so
+// NeedsRehash can tell when a hash was made with older settings (or is a
+// legacy plaintext password) and should be replaced after the next login.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(stored, password string) (bool, error)
+	NeedsRehash(stored string) bool
+	// DummyHash is a hash made with the current settings, to verify against
+	// when there is no stored one so the check takes as long as a real one.
+	DummyHash() string
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+	dummy      string
+}
+
+func NewPasswordHasher(cfg *config.Config) (PasswordHasher, error) {
+	h := &passwordHasher{
+		algorithm:  cfg.PasswordHashAlgorithm,
+		bcryptCost: cfg.BcryptCost,
+	}
+
+	switch h.algorithm {
+	case AlgorithmBcrypt:
+		if h.bcryptCost < bcrypt.MinCost || h.bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+		if cfg.Argon2Memory <= 0 || cfg.Argon2Iterations <= 0 || cfg.Argon2Parallelism <= 0 || cfg.Argon2Parallelism > 255 {
+			return nil, fmt.Errorf("argon2id memory, iterations and parallelism (1-255) must be positive")
+		}
+		h.argon2 = argon2Params{
+			memory:      uint32(cfg.Argon2Memory),
+			iterations:  uint32(cfg.Argon2Iterations),
+			parallelism: uint8(cfg.Argon2Parallelism),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", h.algorithm)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	dummy, err := h.Hash(base64.RawStdEncoding.EncodeToString(secret))
+	if err != nil {
+		return nil, err
+	}
+	h.dummy = dummy
+
+	return h, nil
+}
+
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.argon2.iterations, h.argon2.memory, h.argon2.parallelism, argon2KeyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		h.argon2.memory, h.argon2.iterations, h.argon2.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks password against any supported stored format. Values that
+// are not a well-formed hash are legacy plaintext passwords, even when they
+// happen to start like one, and are compared in constant time.
+func (h *passwordHasher) Verify(stored, password string) (bool, error) {
+	switch {
+	case isBcrypt(stored):
+		err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password))
+		if err == nil {
+			return true, nil
+		}
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+	case strings.HasPrefix(stored, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(stored)
+		if err != nil {
+			break
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(candidate, key) == 1, nil
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1, nil
+}
+
+func (h *passwordHasher) DummyHash() string {
+	return h.dummy
+}
+
+func (h *passwordHasher) NeedsRehash(stored string) bool {
+	switch {
+	case isBcrypt(stored):
+		if h.algorithm != AlgorithmBcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(stored))
+		return err != nil || cost != h.bcryptCost
+	case strings.HasPrefix(stored, argon2idPrefix):
+		if h.algorithm != AlgorithmArgon2id {
+			return true
+		}
+		params, _, _, err := decodeArgon2id(stored)
+		return err != nil || params != h.argon2
+	default:
+		return true
+	}
+}
+
+func isBcrypt(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// decodeArgon2id parses "$argon2id$v=19$m=...,t=...,p=...$salt$key".
+func decodeArgon2id(stored string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if params.iterations == 0 || params.parallelism == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"authz"
+	"user-service/internal/config"
+	"user-service/internal/models"
+)
+
+// Timestamps in issued tokens are kept to the millisecond, so logging out of
+// all sessions can cut off the tokens issued earlier in the same second.
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
+
+// Claims is the payload of the access tokens issued by this service.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+type TokenService struct {
+	method    jwt.SigningMethod
+	key       interface{}
+	verifyKey interface{}
+	issuer    string
+	ttl       time.Duration
+}
+
+// NewTokenService signs with the shared secret for HS256, or with the PEM
+// private key at JWTPrivateKeyPath for RS256.
+func NewTokenService(cfg *config.Config) (*TokenService, error) {
+	ts := &TokenService{
+		issuer: cfg.JWTIssuer,
+		ttl:    cfg.AccessTokenTTL,
+	}
+
+	switch cfg.JWTAlgorithm {
+	case "HS256":
+		if len(cfg.JWTSecret) < authz.MinSecretLength {
+			return nil, fmt.Errorf("JWT_SECRET must be set to at least %d bytes for HS256", authz.MinSecretLength)
+		}
+		ts.method = jwt.SigningMethodHS256
+		ts.key = []byte(cfg.JWTSecret)
+		ts.verifyKey = ts.key
+	case "RS256":
+		pem, err := os.ReadFile(cfg.JWTPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT private key: %v", err)
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWT private key: %v", err)
+		}
+		ts.method = jwt.SigningMethodRS256
+		ts.key = key
+		ts.verifyKey = &key.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.JWTAlgorithm)
+	}
+
+	return ts, nil
+}
+
+// GenerateAccessToken issues a signed token for the user and returns it with
+// its expiry time.
+func (ts *TokenService) GenerateAccessToken(user *models.User) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ts.ttl)
+
+	id, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := Claims{
+		Role: user.GetRole(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   user.GetID(),
+			Issuer:    ts.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(ts.method, claims).SignedString(ts.key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// AccessTokenTTL is the lifetime of the access tokens this service issues.
+func (ts *TokenService) AccessTokenTTL() time.Duration {
+	return ts.ttl
+}
+
+// ParseAccessToken verifies a token issued by GenerateAccessToken and returns
+// its claims.
+func (ts *TokenService) ParseAccessToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(*jwt.Token) (interface{}, error) { return ts.verifyKey, nil },
+		jwt.WithValidMethods([]string{ts.method.Alg()}),
+		jwt.WithIssuer(ts.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// GenerateRefreshToken returns a new opaque refresh token and the hash under
+// which it is stored.
+func (ts *TokenService) GenerateRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the SHA-256 hex digest used to look up a refresh
+// token without storing it in plain text.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
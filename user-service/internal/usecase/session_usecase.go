@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"user-service/internal/models"
+	"user-service/internal/repository"
+	"user-service/internal/service"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+)
+
+// TokenPair is the access and refresh token handed out on login and refresh.
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+type SessionUseCase struct {
+	userRepo     repository.UserRepository
+	refreshRepo  repository.RefreshTokenRepository
+	denylist     repository.TokenDenylistRepository
+	tokenService *service.TokenService
+	refreshTTL   time.Duration
+}
+
+func NewSessionUseCase(
+	userRepo repository.UserRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	denylist repository.TokenDenylistRepository,
+	tokenService *service.TokenService,
+	refreshTTL time.Duration,
+) *SessionUseCase {
+	return &SessionUseCase{
+		userRepo:     userRepo,
+		refreshRepo:  refreshRepo,
+		denylist:     denylist,
+		tokenService: tokenService,
+		refreshTTL:   refreshTTL,
+	}
+}
+
+// StartSession issues the first token pair of a new refresh token family.
+func (s *SessionUseCase) StartSession(ctx context.Context, user *models.User) (*TokenPair, error) {
+	return s.issue(ctx, user, primitive.NewObjectID().Hex())
+}
+
+// Refresh exchanges a refresh token for a new token pair and revokes the old
+// refresh token. Presenting a refresh token that was already rotated means it
+// leaked, so the whole family is revoked.
+func (s *SessionUseCase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, *models.User, error) {
+	hash := service.HashRefreshToken(refreshToken)
+	stored, err := s.refreshRepo.GetByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	if stored.IsRevoked() {
+		if stored.ReplacedBy != "" {
+			return nil, nil, s.revokeReusedFamily(ctx, stored)
+		}
+		return nil, nil, ErrInvalidRefreshToken
+	}
+	if time.Now().Unix() >= stored.ExpiresAt {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil, ErrInvalidRefreshToken
+		}
+		return nil, nil, err
+	}
+
+	next, nextHash, err := s.tokenService.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	rotated, err := s.refreshRepo.Rotate(ctx, hash, nextHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !rotated {
+		// A concurrent request rotated it first; treat it as reuse.
+		return nil, nil, s.revokeReusedFamily(ctx, stored)
+	}
+
+	pair, err := s.issueWithRefreshToken(ctx, user, stored.FamilyID, next, nextHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pair, user, nil
+}
+
+// Logout revokes the caller's access token and, when given, the refresh token
+// family it belongs to.
+func (s *SessionUseCase) Logout(ctx context.Context, claims *service.Claims, refreshToken string) error {
+	if err := s.denylist.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshRepo.GetByHash(ctx, service.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+			return nil
+		}
+		return err
+	}
+	if stored.UserID != claims.Subject {
+		return ErrInvalidRefreshToken
+	}
+	return s.refreshRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// LogoutAllSessions revokes every refresh token of the caller and every
+// access token issued to them so far.
+func (s *SessionUseCase) LogoutAllSessions(ctx context.Context, claims *service.Claims) error {
+	if err := s.refreshRepo.RevokeAllForUser(ctx, claims.Subject); err != nil {
+		return err
+	}
+	return s.denylist.RevokeUserTokensBefore(ctx, claims.Subject, time.Now(), s.tokenService.AccessTokenTTL())
+}
+
+func (s *SessionUseCase) issue(ctx context.Context, user *models.User, familyID string) (*TokenPair, error) {
+	refreshToken, hash, err := s.tokenService.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	return s.issueWithRefreshToken(ctx, user, familyID, refreshToken, hash)
+}
+
+func (s *SessionUseCase) issueWithRefreshToken(ctx context.Context, user *models.User, familyID, refreshToken, hash string) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := s.tokenService.GenerateAccessToken(user)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refreshExpiresAt := now.Add(s.refreshTTL)
+	if err := s.refreshRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.GetID(),
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: refreshExpiresAt.Unix(),
+		CreatedAt: now.Unix(),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (s *SessionUseCase) revokeReusedFamily(ctx context.Context, stored *models.RefreshToken) error {
+	log.Printf("Refresh token reuse detected for user %s, revoking family %s", stored.UserID, stored.FamilyID)
+	if err := s.refreshRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
@@ -19,13 +19,15 @@ var (
 type UserUseCase struct {
 	repo         repository.UserRepository   // Use interface
 	emailService *service.EmailService       // Email service field
+	hasher       service.PasswordHasher
 }
 
 // Updated constructor accepting interface and email service
-func NewUserUseCase(repo repository.UserRepository, emailService *service.EmailService) *UserUseCase {
+func NewUserUseCase(repo repository.UserRepository, emailService *service.EmailService, hasher service.PasswordHasher) *UserUseCase {
 	return &UserUseCase{
 		repo:         repo,
 		emailService: emailService,
+		hasher:       hasher,
 	}
 }
 
@@ -38,6 +40,13 @@ func (u *UserUseCase) RegisterUser(ctx context.Context, user *models.User) error
 		return err
 	}
 
+	hash, err := u.hasher.Hash(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+
+	user.Role = models.RoleCustomer
 	if err := u.repo.CreateUser(ctx, user); err != nil {
 		return err
 	}
@@ -56,15 +65,34 @@ func (u *UserUseCase) AuthenticateUser(ctx context.Context, username, password s
 	user, err := u.repo.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
+			// Take as long as a wrong password, so the response time does
+			// not tell whether the username exists
+			u.hasher.Verify(u.hasher.DummyHash(), password)
 			return nil, ErrInvalidCredentials
 		}
 		return nil, err
 	}
 
-	if user.Password != password {
+	ok, err := u.hasher.Verify(user.Password, password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, ErrInvalidCredentials
 	}
 
+	// Upgrade plaintext passwords and hashes made with older parameters now
+	// that we know the password.
+	if u.hasher.NeedsRehash(user.Password) {
+		if hash, err := u.hasher.Hash(password); err != nil {
+			log.Printf("Failed to rehash password for user %s: %v", user.GetID(), err)
+		} else if err := u.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+			log.Printf("Failed to store rehashed password for user %s: %v", user.GetID(), err)
+		} else {
+			user.Password = hash
+		}
+	}
+
 	return user, nil
 }
 